@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"lab2/learner"
+	"os"
+	"strings"
+)
+
+// runCheck - команда check: классификация слов сохранённым автоматом
+// Слова берутся из аргументов, а если их нет - из стандартного ввода, по одному на строку
+func runCheck(args []string) error {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	modelPath := flags.String("model", "hypothesis.json", "файл с сохранённым автоматом")
+	showPath := flags.Bool("path", false, "выводить путь по состояниям")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	dfa, moore, mealy, err := learner.LoadMachine(*modelPath)
+	if err != nil {
+		return err
+	}
+
+	check := func(word string) {
+		// Для машин с выходами вместо accept/reject выводятся выходы
+		if moore != nil {
+			fmt.Printf("%s\t%s\n", learner.WordOrEpsilon(word), moore.Output(word))
+			return
+		}
+		if mealy != nil {
+			fmt.Printf("%s\t%s\n", learner.WordOrEpsilon(word), strings.Join(mealy.Outputs(word), " "))
+			return
+		}
+
+		internal := word
+		if word != dfa.Epsilon {
+			var err error
+			if internal, err = dfa.SymbolTable().Decode(word); err != nil {
+				fmt.Printf("%s\t%v\n", word, err)
+				return
+			}
+		}
+		accepted, path := dfa.Run(internal)
+		result := "reject"
+		if accepted {
+			result = "accept"
+		}
+		if word == "" {
+			word = dfa.Epsilon
+		}
+		if *showPath {
+			fmt.Printf("%s\t%s\t%s\n", word, result, dfa.FormatPath(internal, path))
+		} else {
+			fmt.Printf("%s\t%s\n", word, result)
+		}
+	}
+
+	if flags.NArg() > 0 {
+		for _, word := range flags.Args() {
+			check(word)
+		}
+		return nil
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		check(strings.TrimRight(scanner.Text(), "\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("ошибка при чтении слов: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"lab2/learner"
+	"strings"
+)
+
+// runDiff - команда diff: сравнение двух сохранённых автоматов
+func runDiff(args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	samples := flags.Int("samples", 5, "сколько слов из каждой разности показать")
+	maxLength := flags.Int("max-length", 20, "наибольшая длина слов в примерах")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		return fmt.Errorf("использование: diff [-samples N] first.json second.json")
+	}
+
+	first, err := learner.LoadDFA(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	second, err := learner.LoadDFA(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	showWord := func(word string) string {
+		if word == "" {
+			return first.Epsilon
+		}
+		return first.SymbolTable().Encode(word)
+	}
+
+	equivalent, _ := first.IsEquivalent(second)
+	if equivalent {
+		fmt.Println("Автоматы эквивалентны: да")
+	} else {
+		fmt.Println("Автоматы эквивалентны: нет")
+	}
+	fmt.Printf("Состояний в A: %d (минимально %d)\n", len(first.States), len(first.Minimize().States))
+	fmt.Printf("Состояний в B: %d (минимально %d)\n", len(second.States), len(second.Minimize().States))
+
+	directions := []struct {
+		Name       string
+		Difference *learner.DFA
+	}{
+		{"A \\ B", first.Difference(second)},
+		{"B \\ A", second.Difference(first)},
+	}
+	for _, direction := range directions {
+		empty, witness := direction.Difference.IsEmpty()
+		if empty {
+			fmt.Printf("%s: пусто\n", direction.Name)
+			continue
+		}
+		fmt.Printf("%s: кратчайшее слово %s\n", direction.Name, showWord(witness))
+		words := direction.Difference.SampleAccepted(*samples, *maxLength)
+		for i := range words {
+			words[i] = showWord(words[i])
+		}
+		fmt.Printf("%s: примеры %s\n", direction.Name, strings.Join(words, " "))
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"lab2/learner"
+	"os"
+	"strings"
+)
+
+// runExport - команда export: выгрузка сохранённого автомата в другом представлении
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	modelPath := flags.String("model", "hypothesis.json", "файл с сохранённым автоматом")
+	format := flags.String("format", "grammar", "формат: grammar, ebnf, antlr, go, dot; для машины Мура также mealy")
+	ruleName := flags.String("rule", "LEARNED", "имя правила для ebnf и antlr")
+	grammarName := flags.String("grammar", "Learned", "имя грамматики лексера для antlr")
+	pkg := flags.String("pkg", "learned", "имя пакета для go")
+	testLimit := flags.Int("tests", 500, "наибольшее число тестов для go (0 - без ограничения)")
+	output := flags.String("o", "", "файл для результата (по умолчанию стандартный вывод)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	dfa, moore, mealy, err := learner.LoadMachine(*modelPath)
+	if err != nil {
+		return err
+	}
+
+	if moore != nil || mealy != nil {
+		result, err := exportOutputMachine(moore, mealy, *format)
+		if err != nil {
+			return err
+		}
+		return writeExport(result, *output)
+	}
+
+	if !dfa.SymbolTable().SingleRune() {
+		return fmt.Errorf("экспорт автомата с многобуквенными символами не поддерживается")
+	}
+
+	var result string
+	switch *format {
+	case "grammar":
+		result = dfa.ExportGrammar()
+	case "ebnf":
+		result = dfa.ExportEBNF(*ruleName)
+	case "antlr":
+		result, err = dfa.ExportANTLR(*grammarName, *ruleName)
+		if err != nil {
+			return err
+		}
+	case "dot":
+		result = dfa.DOT()
+	case "go":
+		return exportGo(dfa, *pkg, *testLimit, *output)
+	default:
+		return fmt.Errorf("неизвестный формат экспорта: %s", *format)
+	}
+
+	return writeExport(result, *output)
+}
+
+// writeExport - вывод результата в файл или на стандартный вывод
+func writeExport(result, output string) error {
+	if output == "" {
+		fmt.Print(result)
+		return nil
+	}
+	if err := os.WriteFile(output, []byte(result), 0644); err != nil {
+		return fmt.Errorf("ошибка при записи результата: %v", err)
+	}
+	return nil
+}
+
+// exportOutputMachine - экспорт машины Мура или Мили
+func exportOutputMachine(moore *learner.MooreMachine, mealy *learner.MealyMachine, format string) (string, error) {
+	switch {
+	case format == "dot" && moore != nil:
+		return moore.DOT(), nil
+	case format == "dot":
+		return mealy.DOT(), nil
+	case format == "mealy" && moore != nil:
+		data, err := json.MarshalIndent(moore.ToMealy(), "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("ошибка при сериализации автомата: %v", err)
+		}
+		return string(data) + "\n", nil
+	}
+	return "", fmt.Errorf("формат %s не поддерживается для машин с выходами", format)
+}
+
+// exportGo - запись распознавателя и тестов к нему рядом: name.go и name_test.go
+func exportGo(dfa *learner.DFA, pkg string, testLimit int, output string) error {
+	if output == "" {
+		return fmt.Errorf("для формата go нужно указать файл через -o")
+	}
+	source, err := dfa.GenerateGo(pkg)
+	if err != nil {
+		return err
+	}
+	testSource, skipped, err := dfa.GenerateGoTest(pkg, testLimit)
+	if err != nil {
+		return err
+	}
+	if skipped > 0 {
+		fmt.Printf("Пропущено слов, не согласованных с автоматом: %d\n", skipped)
+	}
+
+	testOutput := strings.TrimSuffix(output, ".go") + "_test.go"
+	if err := os.WriteFile(output, []byte(source), 0644); err != nil {
+		return fmt.Errorf("ошибка при записи результата: %v", err)
+	}
+	if err := os.WriteFile(testOutput, []byte(testSource), 0644); err != nil {
+		return fmt.Errorf("ошибка при записи тестов: %v", err)
+	}
+	return nil
+}
@@ -1,4 +1,4 @@
-package main
+package learner
 
 import (
 	"bytes"
@@ -7,31 +7,34 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"sort"
 	"strings"
 )
 
 // AskForWord - Спрашивает, является ли данная строка словом языка
 func (et *EquivalenceTable) AskForWord(word string) bool {
-	if learnerMode == "manual" {
+	if et.teacher.Mode == "manual" {
+		et.teacher.Stats.MembershipQueries++
 		// Ручной режим, без изменений
 		var response string
-		fmt.Printf("Является ли '%s' словом языка? (1/0): ", word)
+		fmt.Printf("Является ли '%s' словом языка? (1/0): ", et.display(word))
 		fmt.Scanln(&response)
 
 		switch response {
 		case "1":
-			et.AddWord(word, true)
+			et.recordAnswer(word, true)
 			return true
 		case "0":
-			et.AddWord(word, false)
+			et.recordAnswer(word, false)
 			return true
 		}
 		return false
 	} else {
-		url := fmt.Sprintf("http://%s:%s/checkWord", server, port)
+		url := et.teacher.url("checkWord")
+		et.teacher.Stats.MembershipQueries++
 
 		requestBody, err := json.Marshal(map[string]string{
-			"word": word,
+			"word": et.teacher.toWire(word, et.Epsilon),
 		})
 		if err != nil {
 			log.Printf("Ошибка при формировании тела запроса: %v", err)
@@ -68,10 +71,10 @@ func (et *EquivalenceTable) AskForWord(word string) bool {
 		// log.Printf("Результат разбора: %s", response)
 		switch response {
 		case "1":
-			et.AddWord(word, true)
+			et.recordAnswer(word, true)
 			return true
 		case "0":
-			et.AddWord(word, false)
+			et.recordAnswer(word, false)
 			return true
 		default:
 			log.Printf("Неизвестный ответ от сервера: %s", response)
@@ -82,32 +85,37 @@ func (et *EquivalenceTable) AskForWord(word string) bool {
 
 // AskForWordBatch - Спрашивает, является ли каждое слово в wordsToAsk словом языка
 func (et *EquivalenceTable) AskForWordBatch(wordsToAsk map[string]PrefixAndSuffixForWord) []bool {
-	url := fmt.Sprintf("http://%s:%s/check-word-batch", server, port)
+	url := et.teacher.url("check-word-batch")
 
 	// Собираем список слов для отправки на сервер
 	words := make([]string, 0, len(wordsToAsk))
 	for word := range wordsToAsk {
 		words = append(words, word)
 	}
+	// Слова отправляются в одном и том же порядке от запуска к запуску
+	sort.Strings(words)
+	et.teacher.Stats.MembershipQueries += len(words)
 
 	// Формируем тело запроса
 	type WordsRequest struct {
 		Words []string `json:"wordList"`
 	}
 	requestBody := WordsRequest{
-		Words: words,
+		Words: et.toWire(words),
 	}
 
 	// Сериализуем запрос в JSON
 	jsonBody, err := json.Marshal(requestBody)
 	if err != nil {
-		fmt.Errorf("Ошибка при формировании тела запроса: %v", err)
+		log.Printf("Ошибка при формировании тела запроса: %v", err)
+		return nil
 	}
 
 	// Отправляем POST запрос на сервер
 	resp, err := http.Post(url, "application/json", bytes.NewBuffer(jsonBody))
 	if err != nil {
-		fmt.Errorf("Ошибка при отправке запроса: %v", err)
+		log.Printf("Ошибка при отправке запроса: %v", err)
+		return nil
 	}
 	defer resp.Body.Close()
 
@@ -118,12 +126,14 @@ func (et *EquivalenceTable) AskForWordBatch(wordsToAsk map[string]PrefixAndSuffi
 	var response BoolResponse
 	err = json.NewDecoder(resp.Body).Decode(&response)
 	if err != nil {
-		fmt.Errorf("Ошибка при декодировании JSON: %v", err)
+		log.Printf("Ошибка при декодировании JSON: %v", err)
+		return nil
 	}
 
 	// Проверка на количество слов и полученных результатов
 	if len(response.Bools) != len(words) {
-		fmt.Errorf("Некорректное количество ответов: ожидалось %d, получено %d", len(words), len(response.Bools))
+		log.Printf("Некорректное количество ответов: ожидалось %d, получено %d", len(words), len(response.Bools))
+		return nil
 	}
 
 	// Обрабатываем каждый ответ
@@ -131,23 +141,58 @@ func (et *EquivalenceTable) AskForWordBatch(wordsToAsk map[string]PrefixAndSuffi
 		belonging := response.Bools[i] // Получаем результат для текущего слова (true/false)
 
 		// Добавляем слово в словарь таблицы эквивалентности
-		et.AddWord(word, belonging)
+		et.recordAnswer(word, belonging)
 
 		// Обновляем значения в таблице по всем парам префикс/суффикс для этого слова
 		for _, pair := range wordsToAsk[word].Pairs {
-			if belonging {
-				et.Update(pair.First, pair.Second, '+')
-			} else {
-				et.Update(pair.First, pair.Second, '-')
-			}
+			et.Update(pair.First, pair.Second, CellOf(belonging))
 		}
 	}
 	return response.Bools
 }
 
+// AskForWords - Отвечает на запросы принадлежности для списка слов
+// Известные слова берутся из словаря таблицы, остальные задаются учителю одним пакетом
+func (et *EquivalenceTable) AskForWords(words []string) []bool {
+	wordsToAsk := make(map[string]PrefixAndSuffixForWord)
+	for _, word := range words {
+		word = et.Word(word)
+		if !et.CheckWord(word) {
+			wordsToAsk[word] = PrefixAndSuffixForWord{}
+		}
+	}
+	if len(wordsToAsk) > 0 {
+		if et.teacher.Mode == "manual" {
+			words := make([]string, 0, len(wordsToAsk))
+			for word := range wordsToAsk {
+				words = append(words, word)
+			}
+			sort.Strings(words)
+			for _, word := range words {
+				for !et.AskForWord(word) {
+				}
+			}
+		} else {
+			et.AskForWordBatch(wordsToAsk)
+		}
+	}
+
+	result := make([]bool, len(words))
+	for i, word := range words {
+		result[i] = et.Words.Belongs(et.Word(word))
+	}
+	return result
+}
+
 // AskForTable - Спрашивает, является ли данная таблица искомым автоматом
 func (et *EquivalenceTable) AskForTable() (string, string) {
-	if learnerMode == "manual" {
+	// Незаполненную ячейку нельзя отправить: она ушла бы учителю как "не принадлежит"
+	if err := et.CheckKnown(); err != nil {
+		log.Printf("Таблица не отправлена: %v", err)
+		return "ERROR", "ERROR"
+	}
+	et.teacher.Stats.EquivalenceQueries++
+	if et.teacher.Mode == "manual" {
 		// Ручной режим
 		et.PrintTable()
 		var response, response_type string
@@ -162,16 +207,21 @@ func (et *EquivalenceTable) AskForTable() (string, string) {
 			fmt.Scanln(&response_type)
 		}
 
-		return response, response_type
+		counterexample, err := et.teacher.Symbols.Decode(et.strip(response))
+		if err != nil {
+			log.Printf("Ошибка в контрпримере: %v", err)
+			return "ERROR", "ERROR"
+		}
+		return et.Word(counterexample), response_type
 	} else {
-		mainPrefixes := []string{"ε"} // Добавляем ε как первый главный префикс
+		mainPrefixes := []string{et.Epsilon} // Добавляем ε как первый главный префикс
 		nonMainPrefixes := []string{}
-		suffixes := []string{"ε"} // Добавляем ε как первый суффикс
+		suffixes := []string{et.Epsilon} // Добавляем ε как первый суффикс
 		tableData := []string{}
 
 		// Собираем данные префиксов
-		for _, prefix := range et.Prefixes {
-			if prefix.Value != "ε" { // Пропускаем ε, так как он уже добавлен
+		for _, prefix := range et.Prefixes.All() {
+			if prefix.Value != et.Epsilon { // Пропускаем ε, так как он уже добавлен
 				if prefix.IsMain {
 					mainPrefixes = append(mainPrefixes, prefix.Value)
 				} else {
@@ -181,8 +231,8 @@ func (et *EquivalenceTable) AskForTable() (string, string) {
 		}
 
 		// Собираем суффиксы
-		for _, suffix := range et.Suffixes {
-			if suffix != "ε" { // Пропускаем ε, так как он уже добавлен
+		for _, suffix := range et.Suffixes.All() {
+			if suffix != et.Epsilon { // Пропускаем ε, так как он уже добавлен
 				suffixes = append(suffixes, suffix)
 			}
 		}
@@ -190,30 +240,20 @@ func (et *EquivalenceTable) AskForTable() (string, string) {
 		// Собираем значения таблицы
 		for _, prefix := range mainPrefixes {
 			for _, suffix := range suffixes {
-				val := et.GetValue(prefix, suffix)
-				if val == '+' {
-					tableData = append(tableData, "1")
-				} else {
-					tableData = append(tableData, "0")
-				}
+				tableData = append(tableData, et.GetValue(prefix, suffix).Wire())
 			}
 		}
 		for _, prefix := range nonMainPrefixes {
 			for _, suffix := range suffixes {
-				val := et.GetValue(prefix, suffix)
-				if val == '+' {
-					tableData = append(tableData, "1")
-				} else {
-					tableData = append(tableData, "0")
-				}
+				tableData = append(tableData, et.GetValue(prefix, suffix).Wire())
 			}
 		}
 
-		url := fmt.Sprintf("http://%s:%s/checkTable", server, port)
+		url := et.teacher.url("checkTable")
 		requestBody, err := json.Marshal(map[string]string{
-			"main_prefixes":     strings.Join(mainPrefixes, " "),
-			"non_main_prefixes": strings.Join(nonMainPrefixes, " "),
-			"suffixes":          strings.Join(suffixes, " "),
+			"main_prefixes":     strings.Join(et.toWire(mainPrefixes), " "),
+			"non_main_prefixes": strings.Join(et.toWire(nonMainPrefixes), " "),
+			"suffixes":          strings.Join(et.toWire(suffixes), " "),
 			"table":             strings.Join(tableData, " "),
 		})
 		if err != nil {
@@ -225,14 +265,14 @@ func (et *EquivalenceTable) AskForTable() (string, string) {
 
 		resp, err := http.Post(url, "application/json", bytes.NewBuffer(requestBody))
 		if err != nil {
-			fmt.Errorf("Ошибка при отправке запроса: %v", err)
+			log.Printf("Ошибка при отправке запроса: %v", err)
 			return "ERROR", "ERROR"
 		}
 		defer resp.Body.Close()
 
 		body, err := io.ReadAll(resp.Body)
 		if err != nil {
-			fmt.Errorf("Ошибка при чтении ответа: %v", err)
+			log.Printf("Ошибка при чтении ответа: %v", err)
 			return "ERROR", "ERROR"
 		}
 
@@ -244,7 +284,7 @@ func (et *EquivalenceTable) AskForTable() (string, string) {
 		}
 		err = json.Unmarshal(body, &responseStruct)
 		if err != nil {
-			fmt.Errorf("Ошибка при разборе ответа: %v", err)
+			log.Printf("Ошибка при разборе ответа: %v", err)
 			return "ERROR", "ERROR"
 		}
 
@@ -252,42 +292,35 @@ func (et *EquivalenceTable) AskForTable() (string, string) {
 		if responseStruct.Type == nil {
 			// log.Printf("Таблица подтверждена.")
 			return "true", "" // Автомат угадан
-		} else if *responseStruct.Type {
+		}
+		counterexample, err := et.teacher.fromWire(responseStruct.Response, et.Epsilon)
+		if err != nil {
+			log.Printf("Ошибка в контрпримере: %v", err)
+			return "ERROR", "ERROR"
+		}
+		if *responseStruct.Type {
 			// log.Printf("Контрпример: %s, тип: true", responseStruct.Response)
-			return responseStruct.Response, "true"
+			return counterexample, "true"
 		} else {
 			// log.Printf("Контрпример: %s, тип: false", responseStruct.Response)
-			return responseStruct.Response, "false"
+			return counterexample, "false"
 		}
 	}
 }
 
-// SetModeForMAT - выбор одного из режимов MAT: easy, medium, hard
-func SetModeForMAT(mode string) (int, int) {
-	url := fmt.Sprintf("http://%s:%s/generate", server, port)
-	requestBody, err := json.Marshal(map[string]string{
-		"mode": mode,
-	})
-	if err != nil {
-		fmt.Errorf("Ошибка при формировании тела запроса: %v\n", err)
-	}
-
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(requestBody))
-	if err != nil {
-		fmt.Errorf("Ошибка при отправке запроса: %v\n", err)
-	}
-	defer resp.Body.Close()
-
-	type GenerateResponse struct {
-		MaxLexemeSize     int `json:"maxLexemeSize"`
-		MaxBracketNesting int `json:"maxBracketNesting"`
+// toWire - слова таблицы в записи для MAT-сервера: ε таблицы заменяется на ε учителя
+func (et *EquivalenceTable) toWire(words []string) []string {
+	result := make([]string, len(words))
+	for i, word := range words {
+		result[i] = et.teacher.toWire(word, et.Epsilon)
 	}
-	var response GenerateResponse
+	return result
+}
 
-	err = json.NewDecoder(resp.Body).Decode(&response)
-	if err != nil {
-		fmt.Errorf("Ошибка при декодировании JSON: %v", err)
+// display - запись слова таблицы для вывода: имена символов через разделитель
+func (et *EquivalenceTable) display(word string) string {
+	if word == et.Epsilon || et.teacher == nil {
+		return word
 	}
-
-	return response.MaxLexemeSize, response.MaxBracketNesting
+	return et.teacher.Symbols.Encode(word)
 }
@@ -0,0 +1,108 @@
+package learner
+
+import (
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+)
+
+// sortedWords - известные слова в порядке длины, затем лексикографически
+func sortedWords(words map[string]bool) []string {
+	result := make([]string, 0, len(words))
+	for word := range words {
+		result = append(result, word)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if len(result[i]) != len(result[j]) {
+			return len(result[i]) < len(result[j])
+		}
+		return result[i] < result[j]
+	})
+	return result
+}
+
+// GenerateGo - исходный код распознавателя func Match(s string) bool на Go
+func (dfa *DFA) GenerateGo(pkg string) (string, error) {
+	trimmed := dfa.Trim()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "// Code generated by lab2 export; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&sb, "package %s\n\n", pkg)
+	fmt.Fprintf(&sb, "// Match reports whether s belongs to the learned language.\n")
+	fmt.Fprintf(&sb, "func Match(s string) bool {\n")
+	fmt.Fprintf(&sb, "state := %d\n", trimmed.Start)
+	fmt.Fprintf(&sb, "for _, r := range s {\n")
+	fmt.Fprintf(&sb, "switch state {\n")
+	for i, state := range trimmed.States {
+		fmt.Fprintf(&sb, "case %d:\n", i)
+		fmt.Fprintf(&sb, "switch r {\n")
+		for _, letter := range trimmed.letters() {
+			if target, ok := state.Transitions[letter]; ok {
+				fmt.Fprintf(&sb, "case %q:\nstate = %d\n", []rune(letter)[0], target)
+			}
+		}
+		fmt.Fprintf(&sb, "default:\nreturn false\n}\n")
+	}
+	fmt.Fprintf(&sb, "}\n}\n")
+
+	accepting := make([]string, 0, len(trimmed.States))
+	for i, state := range trimmed.States {
+		if state.Accepting {
+			accepting = append(accepting, fmt.Sprint(i))
+		}
+	}
+	if len(accepting) == 0 {
+		fmt.Fprintf(&sb, "return false\n}\n")
+	} else {
+		fmt.Fprintf(&sb, "switch state {\ncase %s:\nreturn true\n}\nreturn false\n}\n", strings.Join(accepting, ", "))
+	}
+
+	source, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		return "", fmt.Errorf("ошибка при форматировании кода: %v", err)
+	}
+	return string(source), nil
+}
+
+// GenerateGoTest - тесты для распознавателя по словам таблицы, известным на момент сохранения
+// Слова, с которыми автомат не согласен, пропускаются; их число возвращается вторым значением
+func (dfa *DFA) GenerateGoTest(pkg string, limit int) (string, int, error) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "// Code generated by lab2 export; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&sb, "package %s\n\n", pkg)
+	fmt.Fprintf(&sb, "import \"testing\"\n\n")
+	fmt.Fprintf(&sb, "func TestMatch(t *testing.T) {\n")
+	fmt.Fprintf(&sb, "tests := []struct {\nword string\nwant bool\n}{\n")
+
+	words := make(map[string]bool, len(dfa.Words))
+	for word, belonging := range dfa.Words {
+		words[trimEpsilon(word, dfa.Epsilon)] = belonging
+	}
+
+	skipped, added := 0, 0
+	for _, word := range sortedWords(words) {
+		if limit > 0 && added >= limit {
+			break
+		}
+		belonging := words[word]
+		if dfa.Accepts(word) != belonging {
+			skipped++
+			continue
+		}
+		fmt.Fprintf(&sb, "{%q, %t},\n", word, belonging)
+		added++
+	}
+
+	fmt.Fprintf(&sb, "}\n")
+	fmt.Fprintf(&sb, "for _, tt := range tests {\n")
+	fmt.Fprintf(&sb, "if got := Match(tt.word); got != tt.want {\n")
+	fmt.Fprintf(&sb, "t.Errorf(\"Match(%%q) = %%t, want %%t\", tt.word, got, tt.want)\n")
+	fmt.Fprintf(&sb, "}\n}\n}\n")
+
+	source, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		return "", 0, fmt.Errorf("ошибка при форматировании кода: %v", err)
+	}
+	return string(source), skipped, nil
+}
@@ -0,0 +1,102 @@
+package learner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// defaultEpsilon - запись пустого слова по умолчанию
+const defaultEpsilon = "ε"
+
+type Config struct {
+	Alphabet string `json:"alphabet"`
+	// Запись пустого слова в таблице, словаре, сохранённых автоматах и при выводе
+	Epsilon string `json:"epsilon"`
+	// Запись пустого слова в запросах к MAT-серверу; по умолчанию ε, независимо от epsilon
+	WireEpsilon string `json:"wire_epsilon"`
+	LearnerMode string `json:"learner_mode"`
+	ServerAddr  string `json:"server_address"`
+	ServerPort  string `json:"server_port"`
+	MatMode     string `json:"mat_mode"`
+	// Многобуквенные символы алфавита (if, then) в дополнение к буквам alphabet
+	Symbols []string `json:"symbols"`
+	// Разделитель символов в словах для учителя и в сохранённом автомате; обязателен для symbols
+	SymbolSeparator string `json:"symbol_separator"`
+	// Алгоритм обучения: lstar (таблица классов эквивалентности), kv или ttt (дерево различения),
+	// nlstar (резидуальный NFA), moore или mealy (машины с выходами вместо '+'/'-'),
+	// vpa (скобочные языки: автомат с магазинной памятью, управляемой входом),
+	// symbolic (переходы по диапазонам символов для больших алфавитов),
+	// counter (автомат со счётчиком глубины вложенности скобок)
+	Algorithm string `json:"algorithm"`
+	// Открывающие и закрывающие символы для vpa и counter, остальные символы алфавита внутренние
+	CallSymbols   string `json:"call_symbols"`
+	ReturnSymbols string `json:"return_symbols"`
+	// Граница глубины вложенности для vpa и counter; 0 - взять значение, выданное MAT
+	NestingBound int `json:"nesting_bound"`
+	// Число случайных слов и их наибольшая длина для проверки машин с выходами
+	OutputTests      int `json:"output_tests"`
+	OutputTestLength int `json:"output_test_length"`
+	// Файл с размеченными словами (и начальными префиксами и суффиксами для lstar),
+	// ответы из него учителю не задаются
+	SeedFile string `json:"seed_file"`
+	// Ранее угаданный автомат, с которого продолжается обучение
+	PreviousHypothesis string `json:"previous_hypothesis"`
+	// Удалить после обучения lstar суффиксы, не различающие строк таблицы,
+	// и, если recheck_pruned, отправить сокращённую таблицу учителю ещё раз
+	PruneSuffixes bool `json:"prune_suffixes"`
+	RecheckPruned bool `json:"recheck_pruned"`
+	// Сколько слов словаря держать в памяти; остальные сбрасываются в spill_file
+	// (пусто - временный файл). 0 - весь словарь в памяти
+	MemoryWords int    `json:"memory_words"`
+	SpillFile   string `json:"spill_file"`
+	// Файл, в который сохраняется угаданный автомат
+	HypothesisFile string `json:"hypothesis_file"`
+}
+
+// LoadConfig - чтение конфигурации из файла path; незаданные поля получают значения по умолчанию
+func LoadConfig(path string) (*Config, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при открытии файла конфигурации: %v", err)
+	}
+	defer file.Close()
+
+	bytes, err := ioutil.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при чтении файла конфигурации: %v", err)
+	}
+
+	var config Config
+	err = json.Unmarshal(bytes, &config)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при разборе конфигурации: %v", err)
+	}
+
+	config.SetDefaults()
+	return &config, nil
+}
+
+// SetDefaults - значения по умолчанию для незаданных полей: ε, алгоритм lstar,
+// проверка машин с выходами и файл hypothesis.json
+func (config *Config) SetDefaults() {
+	if config.Epsilon == "" {
+		config.Epsilon = defaultEpsilon
+	}
+	if config.WireEpsilon == "" {
+		config.WireEpsilon = defaultEpsilon
+	}
+	if config.Algorithm == "" {
+		config.Algorithm = "lstar"
+	}
+	if config.OutputTests == 0 {
+		config.OutputTests = 1000
+	}
+	if config.OutputTestLength == 0 {
+		config.OutputTestLength = 10
+	}
+	if config.HypothesisFile == "" {
+		config.HypothesisFile = "hypothesis.json"
+	}
+}
@@ -0,0 +1,132 @@
+package learner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CounterState - управляющее состояние автомата со счётчиком
+// Переход выбирается по символу и по тому, равен ли счётчик нулю; отсутствующий переход отвергает слово
+type CounterState struct {
+	Access    string         `json:"access"`
+	Accepting bool           `json:"accepting"` // Заключительное при нулевом счётчике
+	Zero      map[string]int `json:"zero"`      // Переходы при нулевом счётчике
+	NonZero   map[string]int `json:"nonzero"`   // Переходы при ненулевом счётчике
+}
+
+// CounterAutomaton - автомат с одним ограниченным счётчиком
+// Открывающий символ увеличивает счётчик, закрывающий уменьшает, остальные не меняют.
+// Закрывающий символ при нуле и открывающий при счётчике, равном Limit, отвергают слово
+type CounterAutomaton struct {
+	Calls    string         `json:"calls"`
+	Returns  string         `json:"returns"`
+	Alphabet string         `json:"alphabet"`
+	Epsilon  string         `json:"epsilon"`
+	Limit    int            `json:"limit"`
+	Start    int            `json:"start"`
+	States   []CounterState `json:"states"`
+}
+
+// counterDelta - изменение счётчика символом
+func (automaton *CounterAutomaton) counterDelta(letter string) int {
+	switch {
+	case strings.Contains(automaton.Calls, letter):
+		return 1
+	case strings.Contains(automaton.Returns, letter):
+		return -1
+	default:
+		return 0
+	}
+}
+
+// Step - переход по символу из состояния state при значении счётчика counter
+func (automaton *CounterAutomaton) Step(state, counter int, letter string) (int, int, bool) {
+	next := counter + automaton.counterDelta(letter)
+	if next < 0 || next > automaton.Limit {
+		return 0, 0, false
+	}
+	transitions := automaton.States[state].NonZero
+	if counter == 0 {
+		transitions = automaton.States[state].Zero
+	}
+	target, ok := transitions[letter]
+	return target, next, ok
+}
+
+// Accepts - принадлежит ли слово языку автомата
+func (automaton *CounterAutomaton) Accepts(word string) bool {
+	state, counter := automaton.Start, 0
+	for _, letter := range word {
+		var ok bool
+		state, counter, ok = automaton.Step(state, counter, string(letter))
+		if !ok {
+			return false
+		}
+	}
+	return counter == 0 && automaton.States[state].Accepting
+}
+
+// Flatten - ДКА, состояния которого - пары (состояние, значение счётчика)
+func (automaton *CounterAutomaton) Flatten() *DFA {
+	dfa := &DFA{Alphabet: automaton.Alphabet, Epsilon: automaton.Epsilon, Start: 0}
+	type configuration struct{ state, counter int }
+	configs := []configuration{{automaton.Start, 0}}
+	access := []string{""}
+	index := map[configuration]int{configs[0]: 0}
+
+	for i := 0; i < len(configs); i++ {
+		config := configs[i]
+		state := DFAState{
+			Access:      orEpsilon(access[i], automaton.Epsilon),
+			Accepting:   config.counter == 0 && automaton.States[config.state].Accepting,
+			Transitions: make(map[string]int),
+		}
+		for _, letter := range automaton.Alphabet {
+			target, counter, ok := automaton.Step(config.state, config.counter, string(letter))
+			if !ok {
+				continue
+			}
+			next := configuration{target, counter}
+			if _, exists := index[next]; !exists {
+				index[next] = len(configs)
+				configs = append(configs, next)
+				access = append(access, access[i]+string(letter))
+			}
+			state.Transitions[string(letter)] = index[next]
+		}
+		dfa.States = append(dfa.States, state)
+	}
+	// Недостающие переходы ведут в тупиковое состояние
+	return dfa.Complete(automaton.Alphabet)
+}
+
+// String - компактное описание: по строке на переход с условием на счётчик
+func (automaton *CounterAutomaton) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Счётчик: +1 на %s, -1 на %s, граница %d\n", automaton.Calls, automaton.Returns, automaton.Limit)
+	for i, state := range automaton.States {
+		marker := ""
+		if i == automaton.Start {
+			marker += " (начальное)"
+		}
+		if state.Accepting {
+			marker += " (заключительное при 0)"
+		}
+		fmt.Fprintf(&sb, "q%d [%s]%s\n", i, state.Access, marker)
+		for _, part := range []struct {
+			Condition   string
+			Transitions map[string]int
+		}{{"= 0", state.Zero}, {"> 0", state.NonZero}} {
+			letters := make([]string, 0, len(part.Transitions))
+			for letter := range part.Transitions {
+				letters = append(letters, letter)
+			}
+			sort.Strings(letters)
+			for _, letter := range letters {
+				fmt.Fprintf(&sb, "  %s, счётчик %s -> q%d\n", letter, part.Condition, part.Transitions[letter])
+			}
+		}
+	}
+	return sb.String()
+}
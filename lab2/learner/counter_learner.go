@@ -0,0 +1,227 @@
+package learner
+
+import (
+	"fmt"
+)
+
+// counterMerger - классы состояний развёрнутого автомата при сжатии в автомат со счётчиком
+// Для каждого класса известны заключительность при нуле и переходы при нулевом и ненулевом
+// счётчике; -1 в переходе - явный отказ, отсутствие перехода - он ни разу не понадобится
+type counterMerger struct {
+	parent []int
+	accept []int // labelAccept, labelReject или labelUnknown
+	trans  [][2]map[string]int
+}
+
+// find - представитель класса
+func (merger *counterMerger) find(state int) int {
+	for merger.parent[state] != state {
+		state = merger.parent[state]
+	}
+	return state
+}
+
+// clone - копия для пробной склейки
+func (merger *counterMerger) clone() *counterMerger {
+	result := &counterMerger{
+		parent: append([]int{}, merger.parent...),
+		accept: append([]int{}, merger.accept...),
+		trans:  make([][2]map[string]int, len(merger.trans)),
+	}
+	for i, transitions := range merger.trans {
+		for zero := range transitions {
+			result.trans[i][zero] = make(map[string]int)
+			for letter, target := range transitions[zero] {
+				result.trans[i][zero][letter] = target
+			}
+		}
+	}
+	return result
+}
+
+// merge - склейка классов с замыканием: цели одинаковых переходов склеиваются тоже
+func (merger *counterMerger) merge(first, second int) bool {
+	pairs := [][2]int{{first, second}}
+	for len(pairs) > 0 {
+		a, b := merger.find(pairs[0][0]), merger.find(pairs[0][1])
+		pairs = pairs[1:]
+		if a == b {
+			continue
+		}
+		switch {
+		case merger.accept[b] == labelUnknown:
+		case merger.accept[a] == labelUnknown:
+			merger.accept[a] = merger.accept[b]
+		case merger.accept[a] != merger.accept[b]:
+			return false
+		}
+		merger.parent[b] = a
+		for zero := range merger.trans[b] {
+			for letter, target := range merger.trans[b][zero] {
+				existing, exists := merger.trans[a][zero][letter]
+				switch {
+				case !exists:
+					merger.trans[a][zero][letter] = target
+				case existing < 0 || target < 0:
+					if existing != target {
+						return false
+					}
+				default:
+					pairs = append(pairs, [2]int{existing, target})
+				}
+			}
+		}
+	}
+	return true
+}
+
+// CompressCounter - сжатие ДКА для слов глубины не больше limit в автомат со счётчиком
+// Каждому живому состоянию ДКА соответствует значение счётчика - глубина его строки доступа.
+// Состояния с разными значениями счётчика склеиваются жадно, если их переходы не противоречат
+func CompressCounter(dfa *DFA, calls, returns string, limit int) (*CounterAutomaton, error) {
+	automaton := &CounterAutomaton{Calls: calls, Returns: returns, Alphabet: dfa.Alphabet, Epsilon: dfa.Epsilon, Limit: limit}
+
+	// Живые состояния - те, из которых достижимо заключительное
+	live := make(map[int]bool)
+	for changed := true; changed; {
+		changed = false
+		for state, dfaState := range dfa.States {
+			if live[state] {
+				continue
+			}
+			if dfaState.Accepting {
+				live[state], changed = true, true
+				continue
+			}
+			for _, target := range dfaState.Transitions {
+				if live[target] {
+					live[state], changed = true, true
+					break
+				}
+			}
+		}
+	}
+	if !live[dfa.Start] {
+		automaton.States = []CounterState{{Access: dfa.Epsilon, Zero: map[string]int{}, NonZero: map[string]int{}}}
+		return automaton, nil
+	}
+
+	// Значение счётчика в каждом живом состоянии
+	level := map[int]int{dfa.Start: 0}
+	order := []int{dfa.Start}
+	for i := 0; i < len(order); i++ {
+		state := order[i]
+		for _, letter := range dfa.letters() {
+			target, ok := dfa.Step(state, letter)
+			if !ok || !live[target] {
+				continue
+			}
+			counter := level[state] + automaton.counterDelta(letter)
+			if counter < 0 || counter > limit {
+				return nil, fmt.Errorf("слово %s%s имеет глубину вне границ 0..%d, но не отвергается", trimEpsilon(dfa.States[state].Access, dfa.Epsilon), letter, limit)
+			}
+			if previous, visited := level[target]; visited {
+				if previous != counter {
+					return nil, fmt.Errorf("состояние %s достигается при счётчике %d и %d: язык не распознаётся автоматом со счётчиком", dfa.States[target].Access, previous, counter)
+				}
+				continue
+			}
+			level[target] = counter
+			order = append(order, target)
+		}
+	}
+
+	merger := &counterMerger{
+		parent: make([]int, len(dfa.States)),
+		accept: make([]int, len(dfa.States)),
+		trans:  make([][2]map[string]int, len(dfa.States)),
+	}
+	for state := range dfa.States {
+		merger.parent[state] = state
+		merger.trans[state] = [2]map[string]int{{}, {}}
+	}
+	for _, state := range order {
+		zero := 1
+		if level[state] == 0 {
+			zero = 0
+			merger.accept[state] = labelReject
+			if dfa.States[state].Accepting {
+				merger.accept[state] = labelAccept
+			}
+		}
+		for _, letter := range dfa.letters() {
+			target, ok := dfa.Step(state, letter)
+			counter := level[state] + automaton.counterDelta(letter)
+			switch {
+			case counter < 0 || counter > limit:
+				// Такой переход отвергается самим счётчиком
+			case ok && live[target]:
+				merger.trans[state][zero][letter] = target
+			default:
+				merger.trans[state][zero][letter] = -1
+			}
+		}
+	}
+
+	// Жадная склейка: каждое состояние пробуем склеить с уже выбранными по порядку обхода
+	var representatives []int
+	for _, state := range order {
+		merged := false
+		for _, representative := range representatives {
+			candidate := merger.clone()
+			if candidate.merge(representative, state) {
+				merger, merged = candidate, true
+				break
+			}
+		}
+		if !merged {
+			representatives = append(representatives, state)
+		}
+	}
+
+	index := make(map[int]int)
+	for _, representative := range representatives {
+		index[merger.find(representative)] = len(automaton.States)
+		automaton.States = append(automaton.States, CounterState{
+			Access:    dfa.States[representative].Access,
+			Accepting: merger.accept[merger.find(representative)] == labelAccept,
+			Zero:      make(map[string]int),
+			NonZero:   make(map[string]int),
+		})
+	}
+	for _, representative := range representatives {
+		root := merger.find(representative)
+		state := &automaton.States[index[root]]
+		for zero, transitions := range []map[string]int{state.Zero, state.NonZero} {
+			for letter, target := range merger.trans[root][zero] {
+				if target >= 0 {
+					transitions[letter] = index[merger.find(target)]
+				}
+			}
+		}
+	}
+	automaton.Start = index[merger.find(dfa.Start)]
+
+	if equivalent, witness := automaton.Flatten().IsEquivalent(dfa); !equivalent {
+		return nil, fmt.Errorf("автомат со счётчиком расходится с ДКА на слове %s", orEpsilon(witness, dfa.Epsilon))
+	}
+	return automaton, nil
+}
+
+// CompressLearnedCounter - ДКА, изученный алгоритмом TTT, и его последующее сжатие в автомат
+// со счётчиком. Сам счётчик и граница limit в обучении не участвуют: они нужны только
+// сжатию готового ДКА. Если сжать не удалось, возвращается изученный ДКА и ошибка сжатия;
+// иначе гипотеза - развёртка автомата со счётчиком
+func CompressLearnedCounter(et *EquivalenceTable, alphabet, calls, returns string, limit int) (*CounterAutomaton, *DFA, error) {
+	hypothesis, err := LearnTTT(et, alphabet)
+	if err != nil {
+		return nil, nil, err
+	}
+	automaton, err := CompressCounter(hypothesis.Minimize(), calls, returns, limit)
+	if err != nil {
+		return nil, hypothesis, err
+	}
+	flattened := automaton.Flatten()
+	flattened.Words = hypothesis.Words
+	return automaton, flattened, nil
+}
@@ -0,0 +1,363 @@
+package learner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// DFAState - состояние автомата
+type DFAState struct {
+	Access      string         `json:"access"`      // Строка доступа (представитель класса эквивалентности)
+	Accepting   bool           `json:"accepting"`   // Является ли состояние заключительным
+	Transitions map[string]int `json:"transitions"` // Переходы: буква -> номер состояния
+}
+
+// DFA - детерминированный конечный автомат, построенный по таблице классов эквивалентности
+type DFA struct {
+	Alphabet string     `json:"alphabet"`
+	Epsilon  string     `json:"epsilon"`
+	Start    int        `json:"start"`
+	States   []DFAState `json:"states"`
+	// Слова таблицы с ответами учителя на момент сохранения
+	Words map[string]bool `json:"words,omitempty"`
+	// Имена символов и их разделитель в словах, если символы записываются не по одной руне;
+	// в памяти символы автомата - внутренние руны Symbols, в файле - имена
+	Symbols   []string `json:"symbols,omitempty"`
+	Separator string   `json:"separator,omitempty"`
+}
+
+// trimEpsilon - заменяет запись пустого слова epsilon на пустую строку
+func trimEpsilon(word, epsilon string) string {
+	if word == epsilon {
+		return ""
+	}
+	return word
+}
+
+// sortedSuffixes - суффиксы таблицы в фиксированном порядке
+func (et *EquivalenceTable) sortedSuffixes() []string {
+	suffixes := make([]string, 0, et.Suffixes.Len())
+	for _, suffix := range et.Suffixes.All() {
+		suffixes = append(suffixes, suffix)
+	}
+	sort.Strings(suffixes)
+	return suffixes
+}
+
+// sortedPrefixes - префиксы таблицы, от коротких к длинным, ε первым
+func (et *EquivalenceTable) sortedPrefixes() []string {
+	prefixes := make([]string, 0, et.Prefixes.Len())
+	for _, prefix := range et.Prefixes.All() {
+		prefixes = append(prefixes, prefix.Value)
+	}
+	sort.Slice(prefixes, func(i, j int) bool {
+		a, b := et.strip(prefixes[i]), et.strip(prefixes[j])
+		if lengthA, lengthB := utf8.RuneCountInString(a), utf8.RuneCountInString(b); lengthA != lengthB {
+			return lengthA < lengthB
+		}
+		return a < b
+	})
+	return prefixes
+}
+
+// sortedMainPrefixes - главные префиксы таблицы в том же порядке
+func (et *EquivalenceTable) sortedMainPrefixes() []string {
+	var prefixes []string
+	for _, prefix := range et.sortedPrefixes() {
+		if entry, _ := et.Prefixes.Get(prefix); entry.IsMain {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes
+}
+
+// rowKey - строка таблицы для слова в виде ключа; false, если значение какой-то ячейки неизвестно
+func (et *EquivalenceTable) rowKey(word string, suffixes []string) (string, bool) {
+	row := make([]byte, 0, len(suffixes))
+	if _, exists := et.Table[word]; exists {
+		for _, suffix := range suffixes {
+			row = append(row, byte(et.GetValue(word, suffix)))
+		}
+		return string(row), true
+	}
+	// Префикса нет в таблице, восстанавливаем строку по словарю
+	for _, suffix := range suffixes {
+		belonging, ok := et.Words.Get(et.Word(word, suffix))
+		if !ok {
+			return "", false
+		}
+		row = append(row, byte(CellOf(belonging)))
+	}
+	return string(row), true
+}
+
+// BuildDFA - построение автомата по главной части таблицы
+// Таблица должна быть заполнена полностью: неизвестная ячейка исказила бы классы строк
+func (et *EquivalenceTable) BuildDFA(alphabet string) (*DFA, error) {
+	if err := et.CheckKnown(); err != nil {
+		return nil, err
+	}
+	suffixes := et.sortedSuffixes()
+	dfa := &DFA{
+		Alphabet: alphabet,
+		Epsilon:  et.Epsilon,
+		Start:    0,
+	}
+
+	// Каждый новый класс строк главной части - отдельное состояние
+	classes := make(map[string]int)
+	for _, prefix := range et.sortedMainPrefixes() {
+		key, _ := et.rowKey(prefix, suffixes)
+		if _, exists := classes[key]; exists {
+			continue
+		}
+		classes[key] = len(dfa.States)
+		dfa.States = append(dfa.States, DFAState{
+			Access:      prefix,
+			Accepting:   et.GetValue(prefix, et.Epsilon) == CellAccept,
+			Transitions: make(map[string]int),
+		})
+	}
+
+	// Переходы определяются строкой таблицы для продолжения представителя
+	for i := range dfa.States {
+		for _, letter := range alphabet {
+			key, ok := et.rowKey(et.Word(dfa.States[i].Access, string(letter)), suffixes)
+			if !ok {
+				continue
+			}
+			if target, exists := classes[key]; exists {
+				dfa.States[i].Transitions[string(letter)] = target
+			}
+		}
+	}
+	return dfa, nil
+}
+
+// Step - переход из состояния по букве; false, если перехода нет
+func (dfa *DFA) Step(state int, letter string) (int, bool) {
+	if state < 0 || state >= len(dfa.States) {
+		return -1, false
+	}
+	target, ok := dfa.States[state].Transitions[letter]
+	return target, ok
+}
+
+// Run - прогон слова по автомату, возвращает результат и пройденные состояния
+func (dfa *DFA) Run(word string) (bool, []int) {
+	if word == dfa.Epsilon {
+		word = ""
+	}
+	state := dfa.Start
+	path := []int{state}
+	for _, letter := range word {
+		next, ok := dfa.Step(state, string(letter))
+		if !ok {
+			return false, path
+		}
+		state = next
+		path = append(path, state)
+	}
+	return dfa.States[state].Accepting, path
+}
+
+// Accepts - принадлежит ли слово языку автомата
+func (dfa *DFA) Accepts(word string) bool {
+	accepted, _ := dfa.Run(word)
+	return accepted
+}
+
+// SaveDFA - сохранение автомата в файл в формате JSON
+func SaveDFA(dfa *DFA, path string) error {
+	data, err := json.MarshalIndent(encodeDFA(dfa), "", "  ")
+	if err != nil {
+		return fmt.Errorf("ошибка при сериализации автомата: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("ошибка при записи автомата: %v", err)
+	}
+	return nil
+}
+
+// LoadDFA - загрузка автомата из файла
+func LoadDFA(path string) (*DFA, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при открытии файла автомата: %v", err)
+	}
+
+	var dfa DFA
+	if err := json.Unmarshal(data, &dfa); err != nil {
+		return nil, fmt.Errorf("ошибка при разборе автомата: %v", err)
+	}
+	if len(dfa.States) == 0 || dfa.Start < 0 || dfa.Start >= len(dfa.States) {
+		return nil, fmt.Errorf("некорректный автомат: нет начального состояния")
+	}
+	if dfa.Epsilon == "" {
+		dfa.Epsilon = defaultEpsilon
+	}
+	return decodeDFA(&dfa)
+}
+
+// runFrom - прогон слова по автомату из заданного состояния
+func (dfa *DFA) runFrom(state int, word string) bool {
+	for _, letter := range word {
+		next, ok := dfa.Step(state, string(letter))
+		if !ok {
+			return false
+		}
+		state = next
+	}
+	return dfa.States[state].Accepting
+}
+
+// SeparatingSuffixes - суффиксы, различающие все состояния минимального автомата
+// Всегда содержат пустое слово, которое записывается как ε автомата
+func (dfa *DFA) SeparatingSuffixes() []string {
+	suffixes := []string{""}
+	signature := func(state int) string {
+		row := make([]byte, len(suffixes))
+		for i, suffix := range suffixes {
+			if dfa.runFrom(state, suffix) {
+				row[i] = '+'
+			} else {
+				row[i] = '-'
+			}
+		}
+		return string(row)
+	}
+
+	for {
+		rows := make([]string, len(dfa.States))
+		classes := make(map[string][]int)
+		for state := range dfa.States {
+			rows[state] = signature(state)
+			classes[rows[state]] = append(classes[rows[state]], state)
+		}
+		if len(classes) == len(dfa.States) {
+			break
+		}
+
+		// Ищем пару неразличимых состояний, у которой различимы переходы по какой-то букве
+		added := false
+		for _, class := range classes {
+			for i := 0; i < len(class) && !added; i++ {
+				for j := i + 1; j < len(class) && !added; j++ {
+					for _, letter := range dfa.letters() {
+						p, okP := dfa.Step(class[i], letter)
+						q, okQ := dfa.Step(class[j], letter)
+						if !okP || !okQ || rows[p] == rows[q] {
+							continue
+						}
+						for k, suffix := range suffixes {
+							if rows[p][k] != rows[q][k] {
+								suffixes = append(suffixes, letter+suffix)
+								added = true
+								break
+							}
+						}
+						break
+					}
+				}
+			}
+		}
+		if !added {
+			// Автомат не минимален - остальные состояния суффиксами не различить
+			break
+		}
+	}
+
+	for i := range suffixes {
+		suffixes[i] = orEpsilon(suffixes[i], dfa.Epsilon)
+	}
+	return suffixes
+}
+
+// AccessStrings - кратчайшие строки доступа ко всем достижимым состояниям
+// в порядке обхода в ширину: по длине, при равной длине - по буквам алфавита
+func (dfa *DFA) AccessStrings() []string {
+	access := map[int]string{dfa.Start: ""}
+	result := []string{""}
+	queue := []int{dfa.Start}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for _, letter := range dfa.letters() {
+			target, ok := dfa.Step(state, letter)
+			if !ok {
+				continue
+			}
+			if _, visited := access[target]; !visited {
+				access[target] = access[state] + letter
+				result = append(result, access[target])
+				queue = append(queue, target)
+			}
+		}
+	}
+	return result
+}
+
+// TableFromDFA - таблица классов эквивалентности, задающая автомат, для отправки MAT
+// Значения ячеек вычисляются самим автоматом, учителю вопросы не задаются
+func TableFromDFA(dfa *DFA) *EquivalenceTable {
+	minimal := dfa.Minimize()
+	suffixes := minimal.SeparatingSuffixes()
+	access := minimal.AccessStrings()
+
+	et := NewEquivalenceTable(nil, dfa.Epsilon, nil, nil)
+	for _, suffix := range suffixes {
+		et.AddSuffix(suffix)
+	}
+	for _, word := range access {
+		et.AddPrefix(Prefix{Value: et.Word(word), IsMain: true})
+	}
+	for _, word := range access {
+		for _, letter := range minimal.letters() {
+			et.AddPrefix(Prefix{Value: word + letter, IsMain: false})
+		}
+	}
+
+	for _, prefix := range et.Prefixes.All() {
+		for _, suffix := range et.Suffixes.All() {
+			et.SetValue(prefix.Value, suffix, CellOf(minimal.Accepts(et.Word(prefix.Value, suffix))))
+		}
+	}
+	return et
+}
+
+// WordOrEpsilon - заменяет пустую строку на ε
+func WordOrEpsilon(word string) string {
+	return orEpsilon(word, defaultEpsilon)
+}
+
+// orEpsilon - заменяет пустую строку на запись пустого слова epsilon
+func orEpsilon(word, epsilon string) string {
+	if word == "" {
+		return epsilon
+	}
+	return word
+}
+
+// FormatPath - запись пути по состояниям в виде q0 -a-> q1 -b-> q2
+func (dfa *DFA) FormatPath(word string, path []int) string {
+	if word == dfa.Epsilon {
+		word = ""
+	}
+	symbols := dfa.SymbolTable()
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "q%d", path[0])
+	i := 1
+	for _, letter := range word {
+		if i >= len(path) {
+			fmt.Fprintf(&sb, " -%s-> ∅", symbols.Name(letter))
+			break
+		}
+		fmt.Fprintf(&sb, " -%s-> q%d", symbols.Name(letter), path[i])
+		i++
+	}
+	return sb.String()
+}
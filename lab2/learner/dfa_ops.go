@@ -0,0 +1,296 @@
+package learner
+
+import (
+	"fmt"
+	"strings"
+)
+
+// letters - буквы алфавита автомата по порядку
+func (dfa *DFA) letters() []string {
+	result := make([]string, 0, len(dfa.Alphabet))
+	for _, letter := range dfa.Alphabet {
+		result = append(result, string(letter))
+	}
+	return result
+}
+
+// unionAlphabet - объединение двух алфавитов с сохранением порядка букв
+func unionAlphabet(a, b string) string {
+	result := a
+	for _, letter := range b {
+		if !strings.ContainsRune(result, letter) {
+			result += string(letter)
+		}
+	}
+	return result
+}
+
+// Complete - полный автомат над алфавитом: недостающие переходы ведут в тупиковое состояние
+func (dfa *DFA) Complete(alphabet string) *DFA {
+	result := &DFA{
+		Alphabet: alphabet,
+		Epsilon:  dfa.Epsilon,
+		Start:    dfa.Start,
+		States:   make([]DFAState, len(dfa.States)),
+	}
+	sink := -1
+	for i, state := range dfa.States {
+		result.States[i] = DFAState{
+			Access:      state.Access,
+			Accepting:   state.Accepting,
+			Transitions: make(map[string]int),
+		}
+		for _, letter := range alphabet {
+			if target, ok := state.Transitions[string(letter)]; ok {
+				result.States[i].Transitions[string(letter)] = target
+				continue
+			}
+			if sink < 0 {
+				sink = len(dfa.States)
+			}
+			result.States[i].Transitions[string(letter)] = sink
+		}
+	}
+	if sink >= 0 {
+		sinkState := DFAState{Access: "", Accepting: false, Transitions: make(map[string]int)}
+		for _, letter := range alphabet {
+			sinkState.Transitions[string(letter)] = sink
+		}
+		result.States = append(result.States, sinkState)
+	}
+	return result
+}
+
+// Complement - дополнение языка автомата
+func (dfa *DFA) Complement() *DFA {
+	result := dfa.Complete(dfa.Alphabet)
+	for i := range result.States {
+		result.States[i].Accepting = !result.States[i].Accepting
+	}
+	return result
+}
+
+// Product - произведение автоматов, заключительность пары определяется функцией op
+// Строятся только достижимые пары состояний
+func Product(a, b *DFA, op func(bool, bool) bool) *DFA {
+	alphabet := unionAlphabet(a.Alphabet, b.Alphabet)
+	left := a.Complete(alphabet)
+	right := b.Complete(alphabet)
+
+	type pair struct{ First, Second int }
+	result := &DFA{Alphabet: alphabet, Epsilon: a.Epsilon, Start: 0}
+	index := make(map[pair]int)
+	queue := []pair{{left.Start, right.Start}}
+	access := []string{""}
+	index[queue[0]] = 0
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		id := index[current]
+		state := DFAState{
+			Access:      access[id],
+			Accepting:   op(left.States[current.First].Accepting, right.States[current.Second].Accepting),
+			Transitions: make(map[string]int),
+		}
+		if state.Access == "" {
+			state.Access = result.Epsilon
+		}
+		for _, letter := range result.letters() {
+			next := pair{left.States[current.First].Transitions[letter], right.States[current.Second].Transitions[letter]}
+			target, exists := index[next]
+			if !exists {
+				target = len(access)
+				index[next] = target
+				access = append(access, trimEpsilon(state.Access, result.Epsilon)+letter)
+				queue = append(queue, next)
+			}
+			state.Transitions[letter] = target
+		}
+		result.States = append(result.States, state)
+	}
+	return result
+}
+
+// Intersection - пересечение языков
+func (dfa *DFA) Intersection(other *DFA) *DFA {
+	return Product(dfa, other, func(x, y bool) bool { return x && y })
+}
+
+// Union - объединение языков
+func (dfa *DFA) Union(other *DFA) *DFA {
+	return Product(dfa, other, func(x, y bool) bool { return x || y })
+}
+
+// Difference - разность языков
+func (dfa *DFA) Difference(other *DFA) *DFA {
+	return Product(dfa, other, func(x, y bool) bool { return x && !y })
+}
+
+// SymmetricDifference - симметрическая разность языков
+func (dfa *DFA) SymmetricDifference(other *DFA) *DFA {
+	return Product(dfa, other, func(x, y bool) bool { return x != y })
+}
+
+// ShortestAccepted - кратчайшее (и наименьшее лексикографически) слово языка; false, если язык пуст
+func (dfa *DFA) ShortestAccepted() (string, bool) {
+	visited := make([]bool, len(dfa.States))
+	words := make([]string, len(dfa.States))
+	queue := []int{dfa.Start}
+	visited[dfa.Start] = true
+
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		if dfa.States[state].Accepting {
+			return words[state], true
+		}
+		for _, letter := range dfa.letters() {
+			target, ok := dfa.Step(state, letter)
+			if !ok || visited[target] {
+				continue
+			}
+			visited[target] = true
+			words[target] = words[state] + letter
+			queue = append(queue, target)
+		}
+	}
+	return "", false
+}
+
+// IsEmpty - проверка языка на пустоту; если язык не пуст, возвращает кратчайшее его слово
+func (dfa *DFA) IsEmpty() (bool, string) {
+	witness, found := dfa.ShortestAccepted()
+	return !found, witness
+}
+
+// IsUniversal - проверка, что автомат принимает все слова; иначе возвращает кратчайшее отвергаемое слово
+func (dfa *DFA) IsUniversal() (bool, string) {
+	return dfa.Complement().IsEmpty()
+}
+
+// IsSubsetOf - проверка включения языков; иначе возвращает кратчайшее слово из разности
+func (dfa *DFA) IsSubsetOf(other *DFA) (bool, string) {
+	return dfa.Difference(other).IsEmpty()
+}
+
+// IsEquivalent - проверка эквивалентности; иначе возвращает кратчайшее различающее слово
+func (dfa *DFA) IsEquivalent(other *DFA) (bool, string) {
+	return dfa.SymmetricDifference(other).IsEmpty()
+}
+
+// Minimize - минимальный полный автомат (разбиение состояний по Муру)
+func (dfa *DFA) Minimize() *DFA {
+	complete := dfa.Complete(dfa.Alphabet)
+	letters := complete.letters()
+
+	// Достижимые состояния в порядке обхода в ширину
+	order := []int{complete.Start}
+	visited := map[int]bool{complete.Start: true}
+	for i := 0; i < len(order); i++ {
+		for _, letter := range letters {
+			target := complete.States[order[i]].Transitions[letter]
+			if !visited[target] {
+				visited[target] = true
+				order = append(order, target)
+			}
+		}
+	}
+
+	// Начальное разбиение - по заключительности, затем уточняем по классам переходов
+	class := make(map[int]int)
+	for _, state := range order {
+		if complete.States[state].Accepting {
+			class[state] = 1
+		}
+	}
+	for count := 0; ; {
+		keys := make(map[string]int)
+		next := make(map[int]int)
+		for _, state := range order {
+			key := fmt.Sprint(class[state])
+			for _, letter := range letters {
+				key += "," + fmt.Sprint(class[complete.States[state].Transitions[letter]])
+			}
+			if _, exists := keys[key]; !exists {
+				keys[key] = len(keys)
+			}
+			next[state] = keys[key]
+		}
+		class = next
+		if len(keys) == count {
+			break
+		}
+		count = len(keys)
+	}
+
+	// Представитель класса - первое состояние в порядке обхода, то есть с кратчайшей строкой доступа
+	result := &DFA{Alphabet: complete.Alphabet, Epsilon: complete.Epsilon, Start: class[complete.Start], Words: dfa.Words}
+	for _, state := range order {
+		if class[state] < len(result.States) {
+			continue
+		}
+		result.States = append(result.States, DFAState{
+			Access:      complete.States[state].Access,
+			Accepting:   complete.States[state].Accepting,
+			Transitions: make(map[string]int),
+		})
+		for _, letter := range letters {
+			result.States[class[state]].Transitions[letter] = class[complete.States[state].Transitions[letter]]
+		}
+	}
+	return result
+}
+
+// SampleAccepted - до limit слов языка длиной не больше maxLength в порядке длины
+// Слова каждой длины перебираются в глубину, и слово продолжается только буквами, после
+// которых заключительное состояние достижимо ровно за оставшееся число шагов: каждая ветвь
+// перебора даёт слово, а памяти нужно на таблицу достижимости и одно текущее слово
+func (dfa *DFA) SampleAccepted(limit, maxLength int) []string {
+	trimmed := dfa.Trim()
+	letters := trimmed.letters()
+
+	// reach[steps][state] - из state заключительное достижимо ровно за steps шагов
+	reach := make([][]bool, maxLength+1)
+	for steps := range reach {
+		reach[steps] = make([]bool, len(trimmed.States))
+		for state, dfaState := range trimmed.States {
+			if steps == 0 {
+				reach[steps][state] = dfaState.Accepting
+				continue
+			}
+			for _, letter := range letters {
+				if target, ok := trimmed.Step(state, letter); ok && reach[steps-1][target] {
+					reach[steps][state] = true
+					break
+				}
+			}
+		}
+	}
+
+	var result []string
+	var word []string
+	var walk func(state, remaining int)
+	walk = func(state, remaining int) {
+		if remaining == 0 {
+			result = append(result, strings.Join(word, ""))
+			return
+		}
+		for _, letter := range letters {
+			if len(result) >= limit {
+				return
+			}
+			if target, ok := trimmed.Step(state, letter); ok && reach[remaining-1][target] {
+				word = append(word, letter)
+				walk(target, remaining-1)
+				word = word[:len(word)-1]
+			}
+		}
+	}
+	for length := 0; length <= maxLength && len(result) < limit; length++ {
+		if reach[length][trimmed.Start] {
+			walk(trimmed.Start, length)
+		}
+	}
+	return result
+}
@@ -0,0 +1,88 @@
+package learner
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// lengthAtLeast - ДКА над {a, b}, принимающий слова длины не меньше n
+func lengthAtLeast(n int) *DFA {
+	dfa := &DFA{Alphabet: "ab", Epsilon: "ε"}
+	for i := 0; i <= n; i++ {
+		target := i + 1
+		if i == n {
+			target = n
+		}
+		dfa.States = append(dfa.States, DFAState{
+			Access:      WordOrEpsilon(strings.Repeat("a", i)),
+			Accepting:   i == n,
+			Transitions: map[string]int{"a": target, "b": target},
+		})
+	}
+	return dfa
+}
+
+func TestSampleAcceptedMatchesEnumeration(t *testing.T) {
+	// Чётное число букв a, слово не оканчивается на b
+	dfa := &DFA{Alphabet: "ab", Epsilon: "ε", States: []DFAState{
+		{Access: "ε", Accepting: true, Transitions: map[string]int{"a": 1, "b": 2}},
+		{Access: "a", Transitions: map[string]int{"a": 0, "b": 3}},
+		{Access: "b", Transitions: map[string]int{"a": 1, "b": 2}},
+		{Access: "ab", Transitions: map[string]int{"a": 0, "b": 3}},
+	}}
+
+	var want []string
+	for _, word := range allWords("ab", 6) {
+		if accepted, _ := dfa.Run(word); accepted {
+			want = append(want, word)
+		}
+	}
+	for _, limit := range []int{0, 1, 5, len(want), len(want) + 10} {
+		got := dfa.SampleAccepted(limit, 6)
+		expected := want
+		if limit < len(expected) {
+			expected = expected[:limit]
+		}
+		if len(got) != len(expected) {
+			t.Fatalf("limit %d: %d слов, ожидалось %d", limit, len(got), len(expected))
+		}
+		for i := range expected {
+			if got[i] != expected[i] {
+				t.Fatalf("limit %d: слово %d - %q, ожидалось %q", limit, i, got[i], expected[i])
+			}
+		}
+	}
+}
+
+func TestSampleAcceptedLongWords(t *testing.T) {
+	// Все слова короче 40 отвергаются: перебор всех префиксов не закончился бы
+	start := time.Now()
+	got := lengthAtLeast(40).SampleAccepted(3, 45)
+	if len(got) != 3 || got[0] != strings.Repeat("a", 40) {
+		t.Fatalf("получено %q", got)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("выборка заняла %v", elapsed)
+	}
+	if got := lengthAtLeast(40).SampleAccepted(3, 39); len(got) != 0 {
+		t.Fatalf("слов длины до 39 нет, получено %q", got)
+	}
+}
+
+func TestProductUsesAutomatonEpsilon(t *testing.T) {
+	a, b := lengthAtLeast(1), lengthAtLeast(2)
+	for _, dfa := range []*DFA{a, b} {
+		dfa.Epsilon = "eps"
+		dfa.States[0].Access = "eps"
+	}
+	product := a.Intersection(b)
+	if product.States[product.Start].Access != "eps" {
+		t.Fatalf("строка доступа начального состояния %q, ожидалось eps", product.States[product.Start].Access)
+	}
+	for _, state := range product.States {
+		if state.Access != "eps" && strings.Contains(state.Access, "eps") {
+			t.Fatalf("в строке доступа %q осталась запись пустого слова", state.Access)
+		}
+	}
+}
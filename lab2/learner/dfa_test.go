@@ -0,0 +1,29 @@
+package learner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTableFromDFAPrefixOrder(t *testing.T) {
+	target := nthFromEndIsA(3)
+	var first []string
+	for run := 0; run < 20; run++ {
+		var prefixes []string
+		for _, prefix := range TableFromDFA(target).Prefixes.All() {
+			prefixes = append(prefixes, prefix.Value)
+		}
+		if run == 0 {
+			first = prefixes
+			continue
+		}
+		if strings.Join(prefixes, " ") != strings.Join(first, " ") {
+			t.Fatalf("порядок префиксов меняется от вызова к вызову:\n%v\n%v", first, prefixes)
+		}
+	}
+	// Главные префиксы - строки доступа по длине, затем по буквам
+	want := []string{"ε", "a", "aa", "ab", "aaa", "aab", "aba", "abb"}
+	if strings.Join(first[:len(want)], " ") != strings.Join(want, " ") {
+		t.Fatalf("главные префиксы %v, ожидались %v", first[:len(want)], want)
+	}
+}
@@ -0,0 +1,202 @@
+package learner
+
+import (
+	"fmt"
+)
+
+// DTNode - вершина дерева различения
+// Внутренняя вершина хранит различающий суффикс, лист - строку доступа состояния
+type DTNode struct {
+	Discriminator string     // Суффикс внутренней вершины ("" - пустое слово)
+	Access        string     // Строка доступа листа ("" - пустое слово)
+	Children      [2]*DTNode // [0] - слово с суффиксом отвергается, [1] - принимается
+	Parent        *DTNode
+	Temporary     bool // Временный суффикс (для TTT), ещё не заменённый окончательным
+}
+
+// IsLeaf - является ли вершина листом
+func (node *DTNode) IsLeaf() bool {
+	return node.Children[0] == nil && node.Children[1] == nil
+}
+
+// depth - глубина вершины
+func (node *DTNode) depth() int {
+	depth := 0
+	for current := node.Parent; current != nil; current = current.Parent {
+		depth++
+	}
+	return depth
+}
+
+// DiscriminationTree - дерево различения: листья - состояния гипотезы, внутренние вершины - суффиксы
+type DiscriminationTree struct {
+	Root   *DTNode
+	Leaves []*DTNode         // Листья в порядке появления состояний
+	et     *EquivalenceTable // Словарь известных слов и доступ к учителю
+	index  map[*DTNode]int   // Номер состояния для листа
+}
+
+// NewDiscriminationTree - дерево из одного листа для пустого слова
+func NewDiscriminationTree(et *EquivalenceTable) *DiscriminationTree {
+	root := &DTNode{Access: ""}
+	return &DiscriminationTree{
+		Root:   root,
+		Leaves: []*DTNode{root},
+		et:     et,
+		index:  map[*DTNode]int{root: 0},
+	}
+}
+
+// SiftAll - просеивание слов от корня до листьев
+func (tree *DiscriminationTree) SiftAll(words []string) []*DTNode {
+	starts := make([]*DTNode, len(words))
+	for i := range starts {
+		starts[i] = tree.Root
+	}
+	return tree.SiftFrom(starts, words)
+}
+
+// SiftFrom - просеивание слов до листьев, начиная с заданных вершин
+// Вопросы для всех слов на одном шаге задаются одним пакетом
+func (tree *DiscriminationTree) SiftFrom(starts []*DTNode, words []string) []*DTNode {
+	nodes := make([]*DTNode, len(words))
+	copy(nodes, starts)
+	for {
+		pending := make([]int, 0, len(words))
+		queries := make([]string, 0, len(words))
+		for i, node := range nodes {
+			if !node.IsLeaf() {
+				pending = append(pending, i)
+				queries = append(queries, words[i]+node.Discriminator)
+			}
+		}
+		if len(pending) == 0 {
+			return nodes
+		}
+		answers := tree.et.AskForWords(queries)
+		for k, i := range pending {
+			if answers[k] {
+				nodes[i] = nodes[i].Children[1]
+			} else {
+				nodes[i] = nodes[i].Children[0]
+			}
+		}
+	}
+}
+
+// Sift - просеивание одного слова
+func (tree *DiscriminationTree) Sift(word string) *DTNode {
+	return tree.SiftAll([]string{word})[0]
+}
+
+// LCA - наименьший общий предок двух вершин
+func (tree *DiscriminationTree) LCA(a, b *DTNode) *DTNode {
+	depthA, depthB := a.depth(), b.depth()
+	for depthA > depthB {
+		a = a.Parent
+		depthA--
+	}
+	for depthB > depthA {
+		b = b.Parent
+		depthB--
+	}
+	for a != b {
+		a, b = a.Parent, b.Parent
+	}
+	return a
+}
+
+// Split - разделение листа суффиксом discriminator на старое состояние и новое с доступом access
+// Возвращает лист нового состояния
+func (tree *DiscriminationTree) Split(leaf *DTNode, discriminator, access string) (*DTNode, error) {
+	answers := tree.et.AskForWords([]string{leaf.Access + discriminator, access + discriminator})
+	if answers[0] == answers[1] {
+		return nil, fmt.Errorf("суффикс %s не различает %s и %s", tree.et.Word(discriminator), tree.et.Word(leaf.Access), tree.et.Word(access))
+	}
+
+	oldLeaf := &DTNode{Access: leaf.Access, Parent: leaf}
+	newLeaf := &DTNode{Access: access, Parent: leaf}
+	leaf.Access = ""
+	leaf.Discriminator = discriminator
+	if answers[0] {
+		leaf.Children = [2]*DTNode{newLeaf, oldLeaf}
+	} else {
+		leaf.Children = [2]*DTNode{oldLeaf, newLeaf}
+	}
+
+	// Старое состояние сохраняет свой номер
+	state := tree.index[leaf]
+	delete(tree.index, leaf)
+	tree.Leaves[state] = oldLeaf
+	tree.index[oldLeaf] = state
+	tree.index[newLeaf] = len(tree.Leaves)
+	tree.Leaves = append(tree.Leaves, newLeaf)
+	return newLeaf, nil
+}
+
+// LeavesBelow - номера состояний в поддереве вершины
+func (tree *DiscriminationTree) LeavesBelow(node *DTNode) []int {
+	if node.IsLeaf() {
+		return []int{tree.index[node]}
+	}
+	return append(tree.LeavesBelow(node.Children[0]), tree.LeavesBelow(node.Children[1])...)
+}
+
+// IsBelow - лежит ли вершина node строго внутри поддерева вершины root
+func (node *DTNode) IsBelow(root *DTNode) bool {
+	for current := node.Parent; current != nil; current = current.Parent {
+		if current == root {
+			return true
+		}
+	}
+	return false
+}
+
+// Discriminators - суффиксы всех внутренних вершин
+func (tree *DiscriminationTree) Discriminators() []string {
+	var result []string
+	var walk func(node *DTNode)
+	walk = func(node *DTNode) {
+		if node == nil || node.IsLeaf() {
+			return
+		}
+		result = append(result, node.Discriminator)
+		walk(node.Children[0])
+		walk(node.Children[1])
+	}
+	walk(tree.Root)
+	return result
+}
+
+// Hypothesis - автомат по дереву: состояния - листья, переход из s по a - лист, в который просеивается s·a
+func (tree *DiscriminationTree) Hypothesis(alphabet string) *DFA {
+	words := make([]string, 0, len(tree.Leaves)*len(alphabet))
+	accesses := make([]string, len(tree.Leaves))
+	for i, leaf := range tree.Leaves {
+		accesses[i] = leaf.Access
+		for _, letter := range alphabet {
+			words = append(words, leaf.Access+string(letter))
+		}
+	}
+	targets := tree.SiftAll(words)
+	accepting := tree.et.AskForWords(accesses)
+
+	dfa := &DFA{Alphabet: alphabet, Epsilon: tree.et.Epsilon}
+	k := 0
+	for i, leaf := range tree.Leaves {
+		if leaf.Access == "" {
+			dfa.Start = i
+		}
+		state := DFAState{
+			Access:      tree.et.Word(leaf.Access),
+			Accepting:   accepting[i],
+			Transitions: make(map[string]int),
+		}
+		for _, letter := range alphabet {
+			state.Transitions[string(letter)] = tree.index[targets[k]]
+			k++
+		}
+		dfa.States = append(dfa.States, state)
+	}
+	return dfa
+}
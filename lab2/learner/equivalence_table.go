@@ -0,0 +1,321 @@
+package learner
+
+import (
+	"fmt"
+)
+
+// Prefix - Структура для хранения префикса и флага принадлежности к главной части таблицы
+type Prefix struct {
+	Value  string // Сам префикс
+	IsMain bool   // Является ли частью главной таблицы
+}
+
+// EquivalenceTable - Структура таблицы классов эквивалентности
+type EquivalenceTable struct {
+	Prefixes *PrefixSet           // Префиксы в порядке добавления
+	Suffixes *SuffixSet           // Суффиксы в порядке добавления, номер суффикса - номер столбца
+	Table    map[string]*TableRow // Строки таблицы: префикс -> значения по номерам столбцов
+	Words    *WordDictionary      // Словарь слов: слово -> принадлежность к языку
+	Epsilon  string               // Запись пустого слова в префиксах, суффиксах и словаре
+	teacher  *Teacher             // Учитель, которому задаются вопросы
+}
+
+// Pair - структура пары строк
+type Pair struct {
+	First  string
+	Second string
+}
+
+// PrefixAndSuffixForWord - хранит необходимые префиксы и суффиксы для слова
+type PrefixAndSuffixForWord struct {
+	Pairs []Pair
+}
+
+// NewEquivalenceTable - Создание новой таблицы
+// teacher может быть nil, если таблица не задаёт вопросов (например, построена по автомату);
+// пустой epsilon означает ε
+func NewEquivalenceTable(teacher *Teacher, epsilon string, prefixes []Prefix, suffixes []string) *EquivalenceTable {
+	if epsilon == "" {
+		epsilon = defaultEpsilon
+	}
+	table := make(map[string]*TableRow)
+
+	// По умолчанию значения всех ячеек неизвестны
+	for _, prefix := range prefixes {
+		table[prefix.Value] = &TableRow{}
+	}
+
+	return &EquivalenceTable{
+		Prefixes: NewPrefixSet(prefixes...),
+		Suffixes: NewSuffixSet(suffixes...),
+		Table:    table,
+		Words:    NewWordDictionary(epsilon),
+		Epsilon:  epsilon,
+		teacher:  teacher,
+	}
+}
+
+// Word - слово из частей (префикса, букв, суффикса) в записи таблицы: ε частей
+// отбрасывается, а пустое слово записывается как ε таблицы
+func (et *EquivalenceTable) Word(parts ...string) string {
+	var word string
+	for _, part := range parts {
+		word += et.strip(part)
+	}
+	return orEpsilon(word, et.Epsilon)
+}
+
+// strip - слово без ε таблицы: пустое слово как пустая строка
+func (et *EquivalenceTable) strip(word string) string {
+	return trimEpsilon(word, et.Epsilon)
+}
+
+// CheckWord - проверка наличия слова в словаре
+func (et *EquivalenceTable) CheckWord(word string) bool {
+	return et.Words.Has(word)
+}
+
+// AddWord - добавляет новое слово в словарь
+func (et *EquivalenceTable) AddWord(word string, belonging bool) bool {
+	if !et.Words.Has(word) {
+		et.Words.Set(word, belonging)
+		if belonging && et.teacher != nil {
+			et.teacher.Stats.TrueWords++
+		}
+		return true
+	}
+	return false
+}
+
+// GetValue - функция получения значения из таблицы
+func (et *EquivalenceTable) GetValue(prefix string, suffix string) Cell {
+	row, exists := et.Table[prefix]
+	if !exists {
+		return CellUnknown
+	}
+	column, exists := et.Suffixes.Index(suffix)
+	if !exists {
+		return CellUnknown
+	}
+	return row.Get(column)
+}
+
+// SetValue - функция внесения значения в таблицу
+func (et *EquivalenceTable) SetValue(prefix string, suffix string, value Cell) {
+	// Проверка на существование префикса
+	if _, exists := et.Prefixes.Get(prefix); !exists {
+		return
+	}
+
+	// Проверка на существование суффикса
+	column, exists := et.Suffixes.Index(suffix)
+	if !exists {
+		return
+	}
+	et.Table[prefix].Set(column, value)
+}
+
+// AddPrefix - Добавление нового префикса
+func (et *EquivalenceTable) AddPrefix(newPrefix Prefix) bool {
+	if !et.Prefixes.Add(newPrefix) {
+		return false
+	}
+	et.Table[newPrefix.Value] = &TableRow{}
+
+	// Инициализируем значения для каждого суффикса
+	for _, suffix := range et.Suffixes.All() {
+		et.Update(newPrefix.Value, suffix, CellUnknown)
+	}
+	return true
+}
+
+// AddSuffix - Добавление нового суффикса
+func (et *EquivalenceTable) AddSuffix(newSuffix string) bool {
+	if !et.Suffixes.Add(newSuffix) {
+		return false
+	}
+
+	// Инициализируем значения для каждого префикса
+	for _, prefix := range et.Prefixes.All() {
+		et.Update(prefix.Value, newSuffix, CellUnknown)
+	}
+	return true
+}
+
+// Update - обновление значения в таблице и добавление нового слова в словарь
+func (et *EquivalenceTable) Update(prefix, suffix string, value Cell) {
+	if _, exists := et.Table[prefix]; exists {
+		et.SetValue(prefix, suffix, value)
+		if value != CellUnknown {
+			et.Words.Set(et.Word(prefix, suffix), value == CellAccept)
+		}
+	}
+}
+
+// FillUnknown - заполнение пустых ячеек таблицы ответами учителя
+func (et *EquivalenceTable) FillUnknown() {
+	var pairs []Pair
+	var words []string
+	for _, prefix := range et.Prefixes.All() {
+		for _, suffix := range et.Suffixes.All() {
+			if et.GetValue(prefix.Value, suffix) == CellUnknown {
+				pairs = append(pairs, Pair{First: prefix.Value, Second: suffix})
+				words = append(words, et.Word(prefix.Value, suffix))
+			}
+		}
+	}
+	for i, belonging := range et.AskForWords(words) {
+		et.Update(pairs[i].First, pairs[i].Second, CellOf(belonging))
+	}
+}
+
+// UnknownCells - ячейки, о которых учителю ещё не задан вопрос
+func (et *EquivalenceTable) UnknownCells() []Pair {
+	var cells []Pair
+	for _, prefix := range et.Prefixes.All() {
+		for _, suffix := range et.Suffixes.All() {
+			if et.GetValue(prefix.Value, suffix) == CellUnknown {
+				cells = append(cells, Pair{First: prefix.Value, Second: suffix})
+			}
+		}
+	}
+	return cells
+}
+
+// CheckKnown - ошибка, если в таблице остались незаполненные ячейки:
+// по такой таблице нельзя строить гипотезу и отправлять её учителю
+func (et *EquivalenceTable) CheckKnown() error {
+	cells := et.UnknownCells()
+	if len(cells) == 0 {
+		return nil
+	}
+	return fmt.Errorf("в таблице %d незаполненных ячеек, например (%s, %s)", len(cells), cells[0].First, cells[0].Second)
+}
+
+// AreAllPrefixesMain проверяет, являются ли все префиксы главными
+func (et *EquivalenceTable) AreAllPrefixesMain() bool {
+	for _, prefix := range et.Prefixes.All() {
+		if !prefix.IsMain {
+			return false
+		}
+	}
+	return true
+}
+
+// ArePrefixesEquivalent - Проверяет, эквивалентны ли два префикса
+func (et *EquivalenceTable) ArePrefixesEquivalent(prefix1, prefix2 string) bool {
+	// Если хотя бы один префикс отсутствует в таблице, они не эквивалентны
+	row1, exists1 := et.Table[prefix1]
+	row2, exists2 := et.Table[prefix2]
+	if !exists1 || !exists2 {
+		return false
+	}
+
+	// Сравниваем хеши и битовые множества строк
+	return row1.Equal(row2)
+}
+
+// CompleteTable - Приведение таблицы к полному виду
+// Из неглавных префиксов с одной и той же новой строкой главным становится только первый:
+// каждый следующий проверяется уже по классам с повышенным префиксом и оказывается ему
+// эквивалентен. Возвращает, сколько вопросов о продолжениях остальных префиксов так сэкономлено
+func (et *EquivalenceTable) CompleteTable(alphabet string) int {
+	promoted := make(map[string]bool)
+	counted := make(map[string]bool)
+	saved := 0
+	closeRows(et.Prefixes, et.Table, func(prefix string) {
+		et.promote(prefix)
+		promoted[prefix] = true
+	}, func(prefix, representative string) {
+		if promoted[representative] {
+			saved += et.extensionQueries(prefix, alphabet, counted)
+		}
+	})
+	return saved
+}
+
+// extensionQueries - сколько новых вопросов учителю потребовали бы продолжения префикса
+// на одну букву, если бы он стал главным; уже учтённые слова отмечаются в counted
+func (et *EquivalenceTable) extensionQueries(prefix, alphabet string, counted map[string]bool) int {
+	queries := 0
+	for _, letter := range alphabet {
+		extension := et.Word(prefix, string(letter))
+		if _, exists := et.Table[extension]; exists {
+			continue
+		}
+		for _, suffix := range et.Suffixes.All() {
+			word := et.Word(extension, suffix)
+			if !et.CheckWord(word) && !counted[word] {
+				counted[word] = true
+				queries++
+			}
+		}
+	}
+	return queries
+}
+
+// InconsistencyTable - Проверка на противоречивость и исправление
+func (et *EquivalenceTable) InconsistencyTable(alphabet string) bool {
+	// Достаточно сравнить каждый главный префикс с представителем его класса
+	return findEqualMainRows(et.Prefixes, et.Table, func(prefix1, prefix2 string) bool {
+		for _, letter := range alphabet { // Проходим по символам алфавита
+			// Если обе строки продолжений известны полностью, их можно сравнить целиком
+			row1, ok1 := et.Table[et.Word(prefix1, string(letter))]
+			row2, ok2 := et.Table[et.Word(prefix2, string(letter))]
+			if ok1 && ok2 && row1.Complete(et.Suffixes.Len()) && row2.Complete(et.Suffixes.Len()) && row1.Equal(row2) {
+				continue
+			}
+
+			// Ищем суффикс v_k, на котором продолжения расходятся
+			for _, suffix := range et.Suffixes.All() {
+				word1 := et.Word(prefix1, string(letter), suffix)
+				word2 := et.Word(prefix2, string(letter), suffix)
+
+				flag1, ok1 := et.Words.Get(word1)
+				flag2, ok2 := et.Words.Get(word2)
+
+				if !ok1 {
+					et.AskForWord(word1)
+					flag1 = et.Words.Belongs(word1)
+				}
+				if !ok2 {
+					et.AskForWord(word2)
+					flag2 = et.Words.Belongs(word2)
+				}
+
+				// Проверяем на противоречие
+				if flag1 != flag2 {
+					// Найдено противоречие, добавляем новый суффикс a+v_k
+					newSuffix := et.Word(string(letter), suffix)
+					et.teacher.Hooks.inconsistency(et.display(prefix1), et.display(prefix2), et.display(string(letter)), et.display(suffix))
+					et.AddSuffix(newSuffix)
+					return true // Возвращаем true, если было добавлено что-то новое
+				}
+			}
+		}
+		return false // противоречий нет
+	})
+}
+
+// PrintTable - Функция для вывода таблицы в консоль
+func (et *EquivalenceTable) PrintTable() {
+	// Вывод суффиксов
+	fmt.Print("   |")
+	for _, suffix := range et.Suffixes.All() {
+		fmt.Printf("%s|", et.display(suffix))
+	}
+	fmt.Println()
+
+	// Вывод префиксов и значений таблицы
+	for _, prefix := range et.Prefixes.All() {
+		if prefix.IsMain {
+			fmt.Printf("%s(M) ", et.display(prefix.Value))
+		} else {
+			fmt.Printf("%s ", et.display(prefix.Value))
+		}
+		for _, suffix := range et.Suffixes.All() {
+			fmt.Printf("%s ", et.GetValue(prefix.Value, suffix))
+		}
+		fmt.Println()
+	}
+}
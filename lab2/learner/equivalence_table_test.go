@@ -0,0 +1,94 @@
+package learner
+
+import (
+	"testing"
+)
+
+// languageTable - таблица с заданными главными и неглавными префиксами,
+// ячейки которой заполнены принадлежностью слов языку
+func languageTable(mains, nonMains, suffixes []string, language func(string) bool) *EquivalenceTable {
+	var prefixes []Prefix
+	for _, prefix := range mains {
+		prefixes = append(prefixes, Prefix{Value: prefix, IsMain: true})
+	}
+	for _, prefix := range nonMains {
+		prefixes = append(prefixes, Prefix{Value: prefix})
+	}
+	et := NewEquivalenceTable(nil, "", prefixes, suffixes)
+	for _, prefix := range et.Prefixes.All() {
+		for _, suffix := range et.Suffixes.All() {
+			et.Update(prefix.Value, suffix, CellOf(language(et.strip(et.Word(prefix.Value, suffix)))))
+		}
+	}
+	return et
+}
+
+// mainPrefixes - главные префиксы таблицы в порядке добавления
+func mainPrefixes(et *EquivalenceTable) []string {
+	var result []string
+	for _, prefix := range et.Prefixes.All() {
+		if prefix.IsMain {
+			result = append(result, prefix.Value)
+		}
+	}
+	return result
+}
+
+func TestCompleteTableMatchesPairwiseComparison(t *testing.T) {
+	// Число букв a по модулю 3 и последняя буква: у многих неглавных префиксов одна строка
+	language := func(word string) bool {
+		return countLetter(word, 'a')%3 == 1 || (len(word) > 0 && word[len(word)-1] == 'b')
+	}
+	mains := []string{"ε"}
+	nonMains := []string{"a", "b", "aa", "ab", "ba", "bb", "aaa", "aab", "aba", "baa"}
+	suffixes := []string{"ε", "a", "aa"}
+
+	et := languageTable(mains, nonMains, suffixes, language)
+	et.CompleteTable("ab")
+
+	// Прежний алгоритм: неглавный префикс сравнивается со всеми главными,
+	// включая повышенные ранее в том же проходе
+	reference := languageTable(mains, nonMains, suffixes, language)
+	for _, nonMainPrefix := range reference.Prefixes.All() {
+		if nonMainPrefix.IsMain {
+			continue
+		}
+		isEquivalent := false
+		for _, mainPrefix := range reference.Prefixes.All() {
+			if mainPrefix.IsMain && reference.ArePrefixesEquivalent(nonMainPrefix.Value, mainPrefix.Value) {
+				isEquivalent = true
+				break
+			}
+		}
+		if !isEquivalent {
+			reference.Prefixes.Set(Prefix{Value: nonMainPrefix.Value, IsMain: true})
+		}
+	}
+
+	got, want := mainPrefixes(et), mainPrefixes(reference)
+	if len(got) != len(want) {
+		t.Fatalf("главные префиксы %v, ожидались %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("главные префиксы %v, ожидались %v", got, want)
+		}
+	}
+}
+
+func TestCompleteTablePromotesOnePrefixPerNewRow(t *testing.T) {
+	// У a и b одна и та же строка, которой нет в главной части
+	nonEmpty := func(word string) bool { return word != "" }
+	et := languageTable([]string{"ε"}, []string{"a", "b"}, []string{"ε", "a"}, nonEmpty)
+
+	saved := et.CompleteTable("ab")
+
+	got := mainPrefixes(et)
+	if len(got) != 2 || got[1] != "a" {
+		t.Fatalf("главные префиксы %v, ожидались [ε a]", got)
+	}
+	// Сэкономлены вопросы о продолжениях b: baa, bb, bba (ba уже известно как ячейка (b, a))
+	if saved != 3 {
+		t.Fatalf("сэкономлено %d вопросов, ожидалось 3", saved)
+	}
+}
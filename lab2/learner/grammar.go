@@ -0,0 +1,188 @@
+package learner
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Trim - автомат без недостижимых и тупиковых состояний
+// Начальное состояние сохраняется всегда, даже если язык пуст
+func (dfa *DFA) Trim() *DFA {
+	// Достижимые из начального состояния
+	reachable := make([]bool, len(dfa.States))
+	reachable[dfa.Start] = true
+	queue := []int{dfa.Start}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for _, target := range dfa.States[state].Transitions {
+			if !reachable[target] {
+				reachable[target] = true
+				queue = append(queue, target)
+			}
+		}
+	}
+
+	// Из которых достижимо заключительное состояние
+	productive := make([]bool, len(dfa.States))
+	for i, state := range dfa.States {
+		productive[i] = state.Accepting
+	}
+	for changed := true; changed; {
+		changed = false
+		for i, state := range dfa.States {
+			if productive[i] {
+				continue
+			}
+			for _, target := range state.Transitions {
+				if productive[target] {
+					productive[i] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	index := make(map[int]int)
+	result := &DFA{Alphabet: dfa.Alphabet, Epsilon: dfa.Epsilon}
+	for i, state := range dfa.States {
+		if i == dfa.Start || (reachable[i] && productive[i]) {
+			index[i] = len(result.States)
+			result.States = append(result.States, DFAState{
+				Access:      state.Access,
+				Accepting:   state.Accepting,
+				Transitions: make(map[string]int),
+			})
+		}
+	}
+	result.Start = index[dfa.Start]
+	for i, state := range dfa.States {
+		from, kept := index[i]
+		if !kept {
+			continue
+		}
+		for letter, target := range state.Transitions {
+			if to, ok := index[target]; ok {
+				result.States[from].Transitions[letter] = to
+			}
+		}
+	}
+	return result
+}
+
+// grammarRule - альтернатива праволинейного правила: терминал и нетерминал; Target = -1 означает ε-правило
+type grammarRule struct {
+	Letter string
+	Target int
+}
+
+// grammarRules - правила праволинейной грамматики: для каждого нетерминала список альтернатив
+func (dfa *DFA) grammarRules() [][]grammarRule {
+	rules := make([][]grammarRule, len(dfa.States))
+	for i, state := range dfa.States {
+		for _, letter := range dfa.letters() {
+			if target, ok := state.Transitions[letter]; ok {
+				rules[i] = append(rules[i], grammarRule{letter, target})
+			}
+		}
+		if state.Accepting {
+			rules[i] = append(rules[i], grammarRule{"", -1})
+		}
+	}
+	return rules
+}
+
+// nonterminalOrder - нетерминалы в порядке вывода: сначала аксиома
+func (dfa *DFA) nonterminalOrder() []int {
+	order := []int{dfa.Start}
+	for i := range dfa.States {
+		if i != dfa.Start {
+			order = append(order, i)
+		}
+	}
+	return order
+}
+
+// exportRules - общая часть экспортёров: по одной строке на нетерминал с непустым списком альтернатив
+func (dfa *DFA) exportRules(sb *strings.Builder, format string, name func(int) string, alternative func(grammarRule) string) {
+	rules := dfa.grammarRules()
+	for _, state := range dfa.nonterminalOrder() {
+		if len(rules[state]) == 0 {
+			continue
+		}
+		alternatives := make([]string, 0, len(rules[state]))
+		for _, rule := range rules[state] {
+			alternatives = append(alternatives, alternative(rule))
+		}
+		fmt.Fprintf(sb, format, name(state), strings.Join(alternatives, " | "))
+	}
+}
+
+// ExportGrammar - праволинейная грамматика в нотации курса: [S] -> a[N1] | ε
+func (dfa *DFA) ExportGrammar() string {
+	trimmed := dfa.Trim()
+	name := func(state int) string {
+		if state == trimmed.Start {
+			return "[S]"
+		}
+		return fmt.Sprintf("[N%d]", state)
+	}
+
+	var sb strings.Builder
+	trimmed.exportRules(&sb, "%s -> %s\n", name, func(rule grammarRule) string {
+		if rule.Target < 0 {
+			return "ε"
+		}
+		return rule.Letter + name(rule.Target)
+	})
+	return sb.String()
+}
+
+// ExportEBNF - грамматика в ISO EBNF
+func (dfa *DFA) ExportEBNF(ruleName string) string {
+	trimmed := dfa.Trim()
+	name := func(state int) string {
+		if state == trimmed.Start {
+			return ruleName
+		}
+		return fmt.Sprintf("%s_%d", ruleName, state)
+	}
+
+	var sb strings.Builder
+	trimmed.exportRules(&sb, "%s = %s ;\n", name, func(rule grammarRule) string {
+		if rule.Target < 0 {
+			return ""
+		}
+		if strings.Contains(rule.Letter, "\"") {
+			return "'" + rule.Letter + "', " + name(rule.Target)
+		}
+		return "\"" + rule.Letter + "\", " + name(rule.Target)
+	})
+	return sb.String()
+}
+
+// ExportANTLR - файл лексера ANTLR с правилом ruleName и фрагментами для остальных нетерминалов
+func (dfa *DFA) ExportANTLR(grammarName, ruleName string) (string, error) {
+	trimmed := dfa.Trim()
+	if empty, _ := trimmed.IsEmpty(); empty {
+		return "", fmt.Errorf("язык автомата пуст, правило лексера построить нельзя")
+	}
+	ruleName = strings.ToUpper(ruleName)
+	name := func(state int) string {
+		return fmt.Sprintf("%s_%d", ruleName, state)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "lexer grammar %s;\n\n", grammarName)
+	fmt.Fprintf(&sb, "%s : %s ;\n\n", ruleName, name(trimmed.Start))
+	trimmed.exportRules(&sb, "fragment %s : %s ;\n", name, func(rule grammarRule) string {
+		if rule.Target < 0 {
+			return ""
+		}
+		letter := strings.ReplaceAll(rule.Letter, "\\", "\\\\")
+		letter = strings.ReplaceAll(letter, "'", "\\'")
+		return "'" + letter + "' " + name(rule.Target)
+	})
+	return sb.String(), nil
+}
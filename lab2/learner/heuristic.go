@@ -1,4 +1,4 @@
-package main
+package learner
 
 import (
 	"strings"
@@ -8,14 +8,15 @@ import (
 func generateCombinations(s string, k int) []string {
 	var result []string
 	var backtrack func(start int, path []rune)
+	letters := []rune(s)
 
 	backtrack = func(start int, path []rune) {
 		if len(path) == k {
 			result = append(result, string(path))
 			return
 		}
-		for i := start; i < len(s); i++ {
-			path = append(path, rune(s[i]))
+		for i := start; i < len(letters); i++ {
+			path = append(path, letters[i])
 			backtrack(i+1, path)
 			path = path[:len(path)-1]
 		}
@@ -0,0 +1,129 @@
+package learner
+
+// Hooks - подписки на события обучения: для индикаторов хода обучения, метрик и трассировки
+// Любое поле может быть nil. Слова передаются во внешней записи (имена символов через
+// разделитель, пустое слово - ε таблицы). Раунд - номер очередного вопроса о гипотезе.
+// Вызовы идут из цикла обучения синхронно, поэтому подписчик не должен надолго его задерживать
+type Hooks struct {
+	// Начало раунда, до заполнения таблицы
+	OnRoundStart func(round int)
+	// Ответ учителя о принадлежности слова (слова из словаря и выборки не сообщаются)
+	OnMembershipQuery func(word string, belongs bool)
+	// Выход слова от учителя машины Мура или Мили
+	OnOutputQuery func(word, output string)
+	// Префикс перенесён в главную часть таблицы
+	OnPrefixPromoted func(prefix string)
+	// Строки prefix1 и prefix2 равны, а их продолжения на letter различаются по suffix
+	OnInconsistency func(prefix1, prefix2, letter, suffix string)
+	// Гипотеза перед отправкой учителю
+	OnHypothesis func(hypothesis *DFA)
+	// Контрпример учителя и его принадлежность языку
+	OnCounterexample func(word string, belongs bool)
+	// Контрпример к машине Мура или Мили
+	OnOutputCounterexample func(word string)
+	// Конец обучения, в том числе с ошибкой
+	OnDone func(hypothesis *DFA, stats Stats, err error)
+}
+
+// roundStart - начало раунда с номером round
+func (hooks *Hooks) roundStart(round int) {
+	if hooks != nil && hooks.OnRoundStart != nil {
+		hooks.OnRoundStart(round)
+	}
+}
+
+// membershipQuery - ответ учителя о слове
+func (hooks *Hooks) membershipQuery(word string, belongs bool) {
+	if hooks != nil && hooks.OnMembershipQuery != nil {
+		hooks.OnMembershipQuery(word, belongs)
+	}
+}
+
+// outputQuery - выход слова от учителя
+func (hooks *Hooks) outputQuery(word, output string) {
+	if hooks != nil && hooks.OnOutputQuery != nil {
+		hooks.OnOutputQuery(word, output)
+	}
+}
+
+// prefixPromoted - префикс стал главным
+func (hooks *Hooks) prefixPromoted(prefix string) {
+	if hooks != nil && hooks.OnPrefixPromoted != nil {
+		hooks.OnPrefixPromoted(prefix)
+	}
+}
+
+// inconsistency - найдено противоречие таблицы
+func (hooks *Hooks) inconsistency(prefix1, prefix2, letter, suffix string) {
+	if hooks != nil && hooks.OnInconsistency != nil {
+		hooks.OnInconsistency(prefix1, prefix2, letter, suffix)
+	}
+}
+
+// wantsHypothesis - есть ли подписчик на гипотезы: L* строит автомат по таблице только для него
+func (hooks *Hooks) wantsHypothesis() bool {
+	return hooks != nil && hooks.OnHypothesis != nil
+}
+
+// counterexample - контрпример учителя
+func (hooks *Hooks) counterexample(word string, belongs bool) {
+	if hooks != nil && hooks.OnCounterexample != nil {
+		hooks.OnCounterexample(word, belongs)
+	}
+}
+
+// outputCounterexample - контрпример к машине с выходами
+func (hooks *Hooks) outputCounterexample(word string) {
+	if hooks != nil && hooks.OnOutputCounterexample != nil {
+		hooks.OnOutputCounterexample(word)
+	}
+}
+
+// done - обучение закончено
+func (hooks *Hooks) done(hypothesis *DFA, stats Stats, err error) {
+	if hooks != nil && hooks.OnDone != nil {
+		hooks.OnDone(hypothesis, stats, err)
+	}
+}
+
+// roundStart - начало раунда обучения: номер раунда - номер следующей гипотезы
+// Можно вызывать в каждом проходе цикла: о раунде сообщается один раз
+func (teacher *Teacher) roundStart() {
+	round := teacher.Stats.EquivalenceQueries + 1
+	if round > teacher.round {
+		teacher.round = round
+		teacher.Hooks.roundStart(round)
+	}
+}
+
+// roundStart - начало раунда обучения таблицы
+func (et *EquivalenceTable) roundStart() {
+	et.teacher.roundStart()
+}
+
+// reportHypothesis - гипотеза подписчикам; как и в сохранённом автомате, для многобуквенных
+// символов указываются их имена
+func (et *EquivalenceTable) reportHypothesis(hypothesis *DFA) {
+	if !et.teacher.Hooks.wantsHypothesis() {
+		return
+	}
+	if symbols := et.teacher.Symbols; !symbols.Plain() {
+		hypothesis.Symbols = symbols.Names()
+		hypothesis.Separator = symbols.Separator
+	}
+	et.teacher.Hooks.OnHypothesis(hypothesis)
+}
+
+// recordAnswer - ответ учителя о принадлежности: в словарь и подписчикам
+func (et *EquivalenceTable) recordAnswer(word string, belongs bool) {
+	et.AddWord(word, belongs)
+	et.teacher.Hooks.membershipQuery(et.display(word), belongs)
+}
+
+// promote - перенос префикса в главную часть таблицы
+func (et *EquivalenceTable) promote(prefix string) {
+	et.Prefixes.Set(Prefix{Value: prefix, IsMain: true})
+	if et.teacher != nil {
+		et.teacher.Hooks.prefixPromoted(et.display(prefix))
+	}
+}
@@ -0,0 +1,80 @@
+package learner
+
+import (
+	"fmt"
+)
+
+// LearnKV - обучение алгоритмом Кернса-Вазирани
+// Вместо полной таблицы префиксов и суффиксов хранятся строки доступа и дерево различения,
+// поэтому запросов принадлежности задаётся заметно меньше
+func LearnKV(et *EquivalenceTable, alphabet string) (*DFA, error) {
+	tree := NewDiscriminationTree(et)
+	for {
+		et.roundStart()
+		hypothesis := tree.Hypothesis(alphabet)
+		counterexample, done, err := askForHypothesis(et, hypothesis)
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return hypothesis, nil
+		}
+		if err := tree.ProcessCounterexampleKV(hypothesis, counterexample); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// askForHypothesis - запрос эквивалентности для гипотезы в виде таблицы
+// Контрпример заносится в словарь таблицы с ответом учителя
+func askForHypothesis(et *EquivalenceTable, hypothesis *DFA) (string, bool, error) {
+	if err := et.teacher.Err(); err != nil {
+		return "", false, err
+	}
+	et.reportHypothesis(hypothesis)
+	table := TableFromDFA(hypothesis)
+	table.teacher = et.teacher
+	response, responseType := table.AskForTable()
+	switch {
+	case response == "true":
+		// Слова сохраняются в записи пустого слова самого автомата;
+		// словарь с пределом слов в памяти вместе с автоматом не сохраняется
+		if !et.Words.Bounded() {
+			hypothesis.Words = make(map[string]bool, et.Words.Len())
+			et.Words.Range(func(word string, belongs bool) {
+				hypothesis.Words[orEpsilon(et.strip(word), hypothesis.Epsilon)] = belongs
+			})
+		}
+		return "", true, nil
+	case response == "ERROR":
+		return "", false, fmt.Errorf("ошибка при проверке гипотезы учителем")
+	}
+	et.Words.Set(et.Word(response), responseType == "true")
+	et.teacher.Hooks.counterexample(et.display(et.Word(response)), responseType == "true")
+	return et.strip(response), false, nil
+}
+
+// ProcessCounterexampleKV - разбор контрпримера по Кернсу-Вазирани
+// Ищется первый префикс, для которого просеивание расходится с гипотезой, и разделяется
+// состояние, в которое гипотеза переводит предыдущий префикс
+func (tree *DiscriminationTree) ProcessCounterexampleKV(hypothesis *DFA, counterexample string) error {
+	if tree.Root.IsLeaf() {
+		// Гипотеза из одного состояния: ε и контрпример различаются пустым суффиксом
+		_, err := tree.Split(tree.Root, "", counterexample)
+		return err
+	}
+
+	letters := []rune(counterexample)
+	_, path := hypothesis.Run(counterexample)
+	for i := 1; i <= len(letters) && i < len(path); i++ {
+		sifted := tree.Sift(string(letters[:i]))
+		expected := tree.Leaves[path[i]]
+		if sifted == expected {
+			continue
+		}
+		discriminator := string(letters[i-1]) + tree.LCA(sifted, expected).Discriminator
+		_, err := tree.Split(tree.Leaves[path[i-1]], discriminator, string(letters[:i-1]))
+		return err
+	}
+	return fmt.Errorf("контрпример %s не расходится с гипотезой", tree.et.Word(counterexample))
+}
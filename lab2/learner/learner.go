@@ -0,0 +1,266 @@
+package learner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Learner - обучение автомата по конфигурации; всё состояние обучения хранится в нём самом,
+// поэтому в одном процессе можно вести несколько обучений одновременно
+type Learner struct {
+	Hooks *Hooks // Подписчики на события обучения; задаются до Learn
+	// Автоматы, изученные вместе с гипотезой; заполняются Learn
+	Companions Companions
+	config     *Config
+	teacher    *Teacher
+	alphabet   string // Внутренняя запись алфавита: по руне на символ
+}
+
+// Companions - автоматы, изученные вместе с ДКА-гипотезой: заполнены только поля
+// выбранного алгоритма. Сохранять и выводить их - дело вызывающего
+type Companions struct {
+	VPA      *VPA              // vpa: автомат с магазинной памятью, гипотеза - его развёртка
+	Symbolic *SymbolicDFA      // symbolic: автомат с переходами по диапазонам символов
+	Counter  *CounterAutomaton // counter: автомат со счётчиком; nil, если ДКА не сжат
+	NFA      *NFA              // nlstar: резидуальный NFA, гипотеза - его детерминизация
+	// counter: почему ДКА не сжат в автомат со счётчиком; гипотеза тогда - изученный ДКА
+	CounterError error
+	MaxDepth     int // vpa и counter: граница глубины вложенности
+}
+
+// NewLearner - обучение с учителем, заданным в конфигурации
+// Незаданные поля конфигурации заполняются значениями по умолчанию (SetDefaults)
+func NewLearner(config *Config) *Learner {
+	config.SetDefaults()
+	return &Learner{config: config, teacher: NewTeacher(config)}
+}
+
+// Learn - обучение выбранным в конфигурации алгоритмом до угаданной гипотезы
+// Отмена ctx прерывает обучение перед очередным вопросом о гипотезе
+func (learner *Learner) Learn(ctx context.Context) (hypothesis *DFA, stats Stats, err error) {
+	learner.teacher.ctx = ctx
+	learner.teacher.Hooks = learner.Hooks
+	learner.Companions = Companions{}
+	defer func() {
+		learner.Hooks.done(hypothesis, stats, err)
+	}()
+	if learner.config.Algorithm == "moore" || learner.config.Algorithm == "mealy" {
+		return nil, learner.teacher.Stats, fmt.Errorf("машины с выходами обучает LearnOutputs")
+	}
+	symbols, err := NewSymbols(learner.config.Alphabet, learner.config.Symbols, learner.config.SymbolSeparator)
+	if err != nil {
+		return nil, learner.teacher.Stats, err
+	}
+	learner.teacher.Symbols = symbols
+	learner.alphabet = symbols.Alphabet()
+	if learner.config.Algorithm == "vpa" || learner.config.Algorithm == "counter" {
+		if err := checkBracketSymbols(learner.config); err != nil {
+			return nil, learner.teacher.Stats, err
+		}
+	}
+
+	maxLexemeSize, maxBracketNesting, err := learner.teacher.SetMode(learner.config.MatMode)
+	if err != nil {
+		return nil, learner.teacher.Stats, err
+	}
+	log.Printf("Максимальный размер лексеммы: %d", maxLexemeSize)
+
+	if learner.config.Algorithm == "lstar" {
+		hypothesis, err = learner.learnLStar(ctx)
+	} else {
+		hypothesis, err = learner.runAlgorithm(maxBracketNesting)
+	}
+	if !symbols.Plain() {
+		// В файле автомата символы записываются именами
+		if hypothesis != nil {
+			hypothesis.Symbols = symbols.Names()
+			hypothesis.Separator = symbols.Separator
+		}
+		if nfa := learner.Companions.NFA; nfa != nil {
+			nfa.Symbols = symbols.Names()
+			nfa.Separator = symbols.Separator
+		}
+	}
+	return hypothesis, learner.teacher.Stats, err
+}
+
+// newTable - таблица с учителем обучения и словарём, сбрасываемым в файл по конфигурации
+func (learner *Learner) newTable(prefixes []Prefix, suffixes []string) *EquivalenceTable {
+	et := NewEquivalenceTable(learner.teacher, learner.config.Epsilon, prefixes, suffixes)
+	et.Words.SetSpill(learner.config.MemoryWords, learner.config.SpillFile)
+	return et
+}
+
+// closeWords - закрытие словаря таблицы; ошибка работы с файлом сброса становится ошибкой
+// обучения, если другой ошибки не было
+func closeWords(et *EquivalenceTable, err *error) {
+	if closeErr := et.Words.Close(); *err == nil {
+		*err = closeErr
+	}
+}
+
+// runAlgorithm - обучение одним из альтернативных алгоритмов, выбранным в конфигурации
+// maxBracketNesting - глубина вложенности скобок, выданная MAT
+func (learner *Learner) runAlgorithm(maxBracketNesting int) (hypothesis *DFA, err error) {
+	config := learner.config
+	et := learner.newTable(nil, nil)
+	defer closeWords(et, &err)
+	if err := prepareTable(et, config, false); err != nil {
+		return nil, err
+	}
+	alphabet := learner.alphabet
+	switch config.Algorithm {
+	case "vpa", "symbolic", "counter":
+		// Описания этих автоматов записываются по буквам алфавита
+		if !learner.teacher.Symbols.SingleRune() {
+			return nil, fmt.Errorf("алгоритм %s не поддерживает многобуквенные символы", config.Algorithm)
+		}
+	}
+	switch config.Algorithm {
+	case "kv":
+		return LearnKV(et, alphabet)
+	case "ttt":
+		return LearnTTT(et, alphabet)
+	case "nlstar":
+		nfa, hypothesis, err := LearnNLStar(et, alphabet)
+		learner.Companions.NFA = nfa
+		return hypothesis, err
+	case "vpa":
+		return learner.runVPALearner(et, maxBracketNesting)
+	case "symbolic":
+		return learner.runSymbolicLearner(et)
+	case "counter":
+		return learner.runCounterLearner(et, maxBracketNesting)
+	default:
+		return nil, fmt.Errorf("неизвестный алгоритм обучения: %s", config.Algorithm)
+	}
+}
+
+// runVPALearner - обучение автомата с магазинной памятью (Companions.VPA);
+// возвращается его развёртка в ДКА
+func (learner *Learner) runVPALearner(et *EquivalenceTable, maxBracketNesting int) (*DFA, error) {
+	config := learner.config
+	maxDepth, err := nestingBound(config, maxBracketNesting)
+	if err != nil {
+		return nil, err
+	}
+
+	vpa, hypothesis, err := LearnVPA(et, learner.alphabet, config.CallSymbols, config.ReturnSymbols, maxDepth)
+	if err != nil {
+		return nil, err
+	}
+	learner.Companions.VPA = vpa
+	learner.Companions.MaxDepth = maxDepth
+	return hypothesis, nil
+}
+
+// LearnOutputs - обучение машины Мура (moore) или Мили (mealy) с выходами учителя
+// Возвращается машина Мура; машину Мили из неё строит ToMealy. Как и в Learn, отмена ctx
+// прерывает обучение перед очередной проверкой гипотезы, а события сообщаются Hooks
+// (OnRoundStart, OnOutputQuery, OnDone без автомата)
+func (learner *Learner) LearnOutputs(ctx context.Context) (machine *MooreMachine, stats Stats, err error) {
+	config := learner.config
+	teacher := learner.teacher
+	teacher.ctx = ctx
+	teacher.Hooks = learner.Hooks
+	defer func() {
+		learner.Hooks.done(nil, stats, err)
+	}()
+	if config.Algorithm != "moore" && config.Algorithm != "mealy" {
+		return nil, teacher.Stats, fmt.Errorf("алгоритм %s обучает ДКА, а не машину с выходами", config.Algorithm)
+	}
+	if _, _, err := teacher.SetMode(config.MatMode); err != nil {
+		return nil, teacher.Stats, err
+	}
+
+	cache := &cachedOutputOracle{teacher: teacher, epsilon: config.Epsilon, Words: make(map[string]string)}
+	check := manualOutputEquivalence
+	if config.LearnerMode == "manual" {
+		cache.oracle = &manualOutputOracle{epsilon: config.Epsilon}
+	} else {
+		cache.oracle = &httpOutputOracle{server: config.ServerAddr, port: config.ServerPort}
+		check = func(machine *MooreMachine) (string, bool, error) {
+			return FindOutputCounterexample(cache, machine, config.OutputTests, config.OutputTestLength)
+		}
+	}
+	equivalence := func(machine *MooreMachine) (string, bool, error) {
+		if err := teacher.Err(); err != nil {
+			return "", false, err
+		}
+		teacher.Stats.EquivalenceQueries++
+		counterexample, found, err := check(machine)
+		if found {
+			teacher.Hooks.outputCounterexample(orEpsilon(counterexample, config.Epsilon))
+			teacher.roundStart()
+		}
+		return counterexample, found, err
+	}
+
+	teacher.roundStart()
+	machine, err = LearnMoore(cache, config.Alphabet, config.Epsilon, equivalence)
+	return machine, teacher.Stats, err
+}
+
+// runCounterLearner - обучение ДКА алгоритмом TTT и сжатие его в автомат со счётчиком глубины
+// вложенности (Companions.Counter). Если сжать не удалось, причина - в Companions.CounterError,
+// а гипотезой остаётся изученный ДКА
+func (learner *Learner) runCounterLearner(et *EquivalenceTable, maxBracketNesting int) (*DFA, error) {
+	config := learner.config
+	limit, err := nestingBound(config, maxBracketNesting)
+	if err != nil {
+		return nil, err
+	}
+
+	automaton, hypothesis, err := CompressLearnedCounter(et, learner.alphabet, config.CallSymbols, config.ReturnSymbols, limit)
+	if hypothesis == nil {
+		return nil, err
+	}
+	learner.Companions.Counter = automaton
+	learner.Companions.CounterError = err
+	learner.Companions.MaxDepth = limit
+	return hypothesis, nil
+}
+
+// checkBracketSymbols - открывающие и закрывающие символы vpa и counter заданы,
+// входят в алфавит и не пересекаются
+func checkBracketSymbols(config *Config) error {
+	if config.CallSymbols == "" || config.ReturnSymbols == "" {
+		return fmt.Errorf("для %s в конфигурации нужны call_symbols и return_symbols", config.Algorithm)
+	}
+	for _, field := range []struct{ name, letters string }{
+		{"call_symbols", config.CallSymbols},
+		{"return_symbols", config.ReturnSymbols},
+	} {
+		for _, letter := range field.letters {
+			if !strings.ContainsRune(config.Alphabet, letter) {
+				return fmt.Errorf("символ %c из %s не входит в алфавит %s", letter, field.name, config.Alphabet)
+			}
+		}
+	}
+	for _, letter := range config.CallSymbols {
+		if strings.ContainsRune(config.ReturnSymbols, letter) {
+			return fmt.Errorf("символ %c задан и в call_symbols, и в return_symbols", letter)
+		}
+	}
+	return nil
+}
+
+// nestingBound - граница глубины вложенности для vpa и counter: из конфигурации или от MAT
+func nestingBound(config *Config, maxBracketNesting int) (int, error) {
+	if config.NestingBound != 0 {
+		return config.NestingBound, nil
+	}
+	return maxBracketNesting, nil
+}
+
+// runSymbolicLearner - обучение символьного автомата (Companions.Symbolic)
+func (learner *Learner) runSymbolicLearner(et *EquivalenceTable) (*DFA, error) {
+	symbolic, hypothesis, err := LearnSymbolic(et, learner.alphabet)
+	if err != nil {
+		return nil, err
+	}
+	learner.Companions.Symbolic = symbolic
+	return hypothesis, nil
+}
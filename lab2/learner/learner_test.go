@@ -0,0 +1,38 @@
+package learner
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestNewLearnerAppliesDefaults(t *testing.T) {
+	config := &Config{Alphabet: "ab", Epsilon: "eps"}
+	NewLearner(config)
+	if config.Algorithm != "lstar" || config.WireEpsilon != "ε" || config.HypothesisFile != "hypothesis.json" {
+		t.Fatalf("значения по умолчанию не заданы: %+v", config)
+	}
+	if config.Epsilon != "eps" {
+		t.Fatalf("заданный epsilon заменён на %q", config.Epsilon)
+	}
+}
+
+func TestLearnRejectsBracketSymbolsOutsideAlphabet(t *testing.T) {
+	tests := []struct {
+		calls, returns, want string
+	}{
+		{"[", ")", "call_symbols"},
+		{"(", "]", "return_symbols"},
+		{"(", "(", "и в call_symbols, и в return_symbols"},
+	}
+	for _, algorithm := range []string{"vpa", "counter"} {
+		for _, test := range tests {
+			// Сервер не задан: до вопросов учителю обучение доходить не должно
+			config := &Config{Alphabet: "()a", Algorithm: algorithm, CallSymbols: test.calls, ReturnSymbols: test.returns}
+			_, _, err := NewLearner(config).Learn(context.Background())
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("%s, %q/%q: ошибка %v, ожидалось упоминание %q", algorithm, test.calls, test.returns, err, test.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package learner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// learnLStar - обучение алгоритмом L* с эвристикой поиска алфавита для eol
+func (learner *Learner) learnLStar(ctx context.Context) (hypothesis *DFA, err error) {
+	config := learner.config
+	alphabet := learner.alphabet
+	epsilon := config.Epsilon
+	heuristicAdded := false
+	eolAlphabet := ""
+
+	IsDone := false
+
+	// Инициализируем таблицу с картами префиксов и суффиксов
+	prefixes := []Prefix{{Value: epsilon, IsMain: true}}
+	suffixes := []string{epsilon}
+
+	et := learner.newTable(prefixes, suffixes)
+	defer closeWords(et, &err)
+	useEol := true
+
+	// Начальные данные из выборки и прежнего автомата
+	if err := prepareTable(et, config, true); err != nil {
+		return nil, err
+	}
+
+	// Пока таблица не угадана
+	for !IsDone {
+		// Обучение можно прервать между раундами через контекст
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		et.roundStart()
+		wordsToAsk := make(map[string]PrefixAndSuffixForWord)
+		// Заполняем пустые значения таблицы
+		for _, prefix := range et.Prefixes.All() {
+			for _, suffix := range et.Suffixes.All() {
+				// Если ячейка пуста
+				if et.GetValue(prefix.Value, suffix) == CellUnknown {
+					word := et.Word(prefix.Value, suffix)
+					// Проверяем наличие слова в словаре
+					if et.CheckWord(word) {
+						// Ответ берём из словаря
+						et.Update(prefix.Value, suffix, CellOf(et.Words.Belongs(word)))
+					} else {
+						// Иначе сохраняем для вопроса
+						// Проверяем, существует ли уже такое слово в карте
+						if _, exists := wordsToAsk[word]; !exists {
+							// Если слова нет, создаем новую запись с пустым списком пар
+							wordsToAsk[word] = PrefixAndSuffixForWord{
+								Pairs: make([]Pair, 0),
+							}
+						}
+						prefixSuffix := wordsToAsk[word]
+						// Добавляем текущие префикс и суффикс в список пар для данного слова
+						prefixSuffix.Pairs = append(wordsToAsk[word].Pairs, Pair{
+							First:  prefix.Value,
+							Second: suffix,
+						})
+						wordsToAsk[word] = prefixSuffix
+					}
+				}
+			}
+		}
+		// Создаем дополнение таблицы для префиксов
+		for _, oldPrefix := range et.Prefixes.All() {
+			// Для каждого символа алфавита
+			for _, letter := range alphabet {
+				if strings.Contains(eolAlphabet, string(letter)) && !useEol {
+					continue
+				}
+				// Создаем новые префиксы на основе главных префиксов
+				if oldPrefix.IsMain {
+					prefix := Prefix{
+						Value:  et.Word(oldPrefix.Value, string(letter)),
+						IsMain: false,
+					}
+					// Если префикс удалось добавить
+					if et.AddPrefix(prefix) {
+						// По необходимости задаём вопросы MAT, заполняем таблицу
+						for _, suffix := range et.Suffixes.All() {
+							word := et.Word(prefix.Value, suffix)
+
+							// Проверяем наличие слова в словаре
+							if et.CheckWord(word) {
+								// Ответ берём из словаря
+								et.Update(prefix.Value, suffix, CellOf(et.Words.Belongs(word)))
+							} else {
+								// Проверяем, существует ли уже такое слово в карте
+								if _, exists := wordsToAsk[word]; !exists {
+									// Если слова нет, создаем новую запись с пустым списком пар
+									wordsToAsk[word] = PrefixAndSuffixForWord{
+										Pairs: make([]Pair, 0),
+									}
+								}
+								prefixSuffix := wordsToAsk[word]
+								// Добавляем текущие префикс и суффикс в список пар для данного слова
+								prefixSuffix.Pairs = append(wordsToAsk[word].Pairs, Pair{
+									First:  prefix.Value,
+									Second: suffix,
+								})
+								wordsToAsk[word] = prefixSuffix
+							}
+						}
+					}
+				}
+			}
+		}
+		et.AskForWordBatch(wordsToAsk)
+
+		//wordsToAsk = make([]string, 0)
+		wordsToAsk = make(map[string]PrefixAndSuffixForWord)
+		// Проверяем таблицу на полноту и приводим к полному виду
+		et.teacher.Stats.SavedExtensionQueries += et.CompleteTable(alphabet)
+
+		// Проверка, являются ли все префиксы главными
+		if !et.AreAllPrefixesMain() {
+
+			inconsistency := true
+			for inconsistency {
+				if et.InconsistencyTable(alphabet) {
+					// Заполняем пустые значения таблицы
+					for _, prefix := range et.Prefixes.All() {
+						for _, suffix := range et.Suffixes.All() {
+							// Если ячейка пуста
+							if et.GetValue(prefix.Value, suffix) == CellUnknown {
+								word := et.Word(prefix.Value, suffix)
+								// Проверяем наличие слова в словаре
+								if et.CheckWord(word) {
+									// Ответ берём из словаря
+									et.Update(prefix.Value, suffix, CellOf(et.Words.Belongs(word)))
+								} else { // Иначе спрашиваем
+									// Проверяем, существует ли уже такое слово в карте
+									if _, exists := wordsToAsk[word]; !exists {
+										// Если слова нет, создаем новую запись с пустым списком пар
+										wordsToAsk[word] = PrefixAndSuffixForWord{
+											Pairs: make([]Pair, 0),
+										}
+									}
+									prefixSuffix := wordsToAsk[word]
+									// Добавляем текущие префикс и суффикс в список пар для данного слова
+									prefixSuffix.Pairs = append(wordsToAsk[word].Pairs, Pair{
+										First:  prefix.Value,
+										Second: suffix,
+									})
+									wordsToAsk[word] = prefixSuffix
+								}
+							}
+						}
+					}
+
+					et.AskForWordBatch(wordsToAsk)
+					wordsToAsk = make(map[string]PrefixAndSuffixForWord)
+				} else {
+					inconsistency = false
+				}
+			}
+
+			// Гипотеза строится по таблице, только если на неё подписаны
+			if et.teacher.Hooks.wantsHypothesis() && et.CheckKnown() == nil {
+				if hypothesis, err := et.BuildDFA(alphabet); err == nil {
+					et.reportHypothesis(hypothesis)
+				}
+			}
+			// отправляем таблицу MAT
+			response, responseType := et.AskForTable()
+			if response == "ERROR" {
+				return nil, fmt.Errorf("ошибка при проверке таблицы учителем")
+			}
+			// Если угадали, то конец, меняем флаг, иначе - добавляем новые суффиксы
+			if response == "true" {
+				IsDone = true
+			} else {
+				if responseType == "true" {
+					// fmt.Printf("Контрпример лернера: %s\n", response)
+					et.Words.Set(response, true)
+				} else {
+					// fmt.Printf("Контрпример мата: %s\n", response)
+
+					et.Words.Set(response, false)
+				}
+				et.teacher.Hooks.counterexample(et.display(response), responseType == "true")
+				// Суффиксы отрезаются по символам, а не по байтам
+				counterexample := []rune(et.strip(response))
+				for i := 0; i < len(counterexample); i++ {
+					et.AddSuffix(string(counterexample[i:]))
+				}
+				_, removedNumber := RemoveChars(eolAlphabet, response)
+				if removedNumber > 0 {
+					// fmt.Println("Используем eol")
+					useEol = true
+				} else {
+					// fmt.Println("Не используем eol")
+					useEol = false
+				}
+				//if heuristicAdded {
+				//	fmt.Printf("Добавил контпример в префиксы: %s\n", response)
+				//	prefix := Prefix{
+				//		Value:  response,
+				//		IsMain: true,
+				//	}
+				//	et.AddPrefix(prefix)
+				//}
+			}
+		}
+		// fmt.Printf("Количество угаданных слов: %d\n", et.teacher.Stats.TrueWords)
+
+		counterEolAlphabets := 0
+		if et.teacher.Stats.TrueWords > 5000 && !heuristicAdded {
+			heuristicAdded = true
+			OriginalWordsToAsk := make(map[string]PrefixAndSuffixForWord)
+			et.Words.Range(func(word string, belongs bool) {
+				// Пустое слово при удалении символов не меняется, спрашивать его незачем
+				if belongs && word != et.Epsilon {
+					OriginalWordsToAsk[word] = PrefixAndSuffixForWord{}
+				}
+			})
+			eolFindFlag := false
+			for length := utf8.RuneCountInString(alphabet) - 4; length > 0; length-- {
+				if eolFindFlag {
+					break
+				}
+				combinations := generateCombinations(alphabet, length)
+				for _, subAlphabet := range combinations {
+					NewWordsToAsk := make(map[string]PrefixAndSuffixForWord)
+					// fmt.Printf("Проверка для подалфавита: %s\n", subAlphabet)
+					emptyWord := false
+					for word := range OriginalWordsToAsk {
+						NewWord, _ := RemoveChars(subAlphabet, word)
+						if NewWord == "" {
+							emptyWord = true
+							break
+						}
+						NewWordsToAsk[NewWord] = PrefixAndSuffixForWord{}
+					}
+					if !emptyWord {
+						responseList := et.AskForWordBatch(NewWordsToAsk)
+						// countingOfFalse := 0
+						responseWithFalse := false
+						for _, response := range responseList {
+							if !response {
+								responseWithFalse = true
+							}
+						}
+						// fmt.Printf("Число ошибок: %d\n", countingOfFalse)
+						if !responseWithFalse {
+							if eolAlphabet != "" {
+								eolAlphabet = Intersection(eolAlphabet, subAlphabet)
+							} else {
+								eolAlphabet = subAlphabet
+							}
+
+							fmt.Printf("Алфавит для eol: %s\n", eolAlphabet)
+							counterEolAlphabets++
+							eolFindFlag = true
+							//break
+						}
+					}
+				}
+			}
+			fmt.Printf("Количество найденных алфавитов: %d\n", counterEolAlphabets)
+		}
+
+	}
+	if config.PruneSuffixes {
+		if err := pruneSuffixes(et, config.RecheckPruned); err != nil {
+			return nil, err
+		}
+	}
+	// et.PrintTable()
+	// Сохраняем угаданный автомат
+	hypothesis, err = et.BuildDFA(alphabet)
+	if err != nil {
+		return nil, err
+	}
+	hypothesis.Words = et.Words.Map()
+	return hypothesis, nil
+}
@@ -0,0 +1,181 @@
+package learner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// randomDFA - полный ДКА из states состояний со случайными переходами и метками
+func randomDFA(random *rand.Rand, states int, alphabet string) *DFA {
+	dfa := &DFA{Alphabet: alphabet, Epsilon: "ε"}
+	for i := 0; i < states; i++ {
+		state := DFAState{Access: "ε", Accepting: random.Intn(2) == 0, Transitions: make(map[string]int)}
+		for _, letter := range alphabet {
+			state.Transitions[string(letter)] = random.Intn(states)
+		}
+		dfa.States = append(dfa.States, state)
+	}
+	return dfa
+}
+
+// nthFromEndIsA - ДКА языка слов над {a, b}, в которых n-я с конца буква - a
+// Минимальный автомат запоминает n последних букв и имеет 2^n состояний
+func nthFromEndIsA(n int) *DFA {
+	dfa := &DFA{Alphabet: "ab", Epsilon: "ε"}
+	mask := 1<<n - 1
+	for window := 0; window <= mask; window++ {
+		dfa.States = append(dfa.States, DFAState{
+			Access:    "ε",
+			Accepting: window>>(n-1)&1 == 1,
+			Transitions: map[string]int{
+				"a": (window<<1 | 1) & mask,
+				"b": (window << 1) & mask,
+			},
+		})
+	}
+	return dfa
+}
+
+// wireWord - слово MAT-сервера без записи пустого слова
+func wireWord(word string) string {
+	if word == "ε" {
+		return ""
+	}
+	return word
+}
+
+// tableCounterexample - кратчайшее слово, на котором автомат по присланной таблице
+// расходится с target; false - таблица задаёт тот же язык
+func tableCounterexample(target *DFA, request map[string]string) (string, bool, bool) {
+	fields := func(key string) []string {
+		if request[key] == "" {
+			return nil
+		}
+		return strings.Split(request[key], " ")
+	}
+	mains, nonMains, suffixes, values := fields("main_prefixes"), fields("non_main_prefixes"), fields("suffixes"), fields("table")
+
+	rows := make(map[string]string)
+	k := 0
+	for _, prefix := range append(append([]string{}, mains...), nonMains...) {
+		rows[wireWord(prefix)] = strings.Join(values[k:k+len(suffixes)], "")
+		k += len(suffixes)
+	}
+	states := make(map[string]string)
+	for _, prefix := range mains {
+		if _, exists := states[rows[wireWord(prefix)]]; !exists {
+			states[rows[wireWord(prefix)]] = wireWord(prefix)
+		}
+	}
+	epsilon := 0
+	for i, suffix := range suffixes {
+		if wireWord(suffix) == "" {
+			epsilon = i
+		}
+	}
+
+	// Обход пар (главный префикс гипотезы, состояние target) в ширину
+	type pair struct {
+		access string
+		state  int
+	}
+	start := pair{"", target.Start}
+	words := map[pair]string{start: ""}
+	queue := []pair{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		word := words[current]
+		if (rows[current.access][epsilon] == '1') != target.States[current.state].Accepting {
+			return word, target.States[current.state].Accepting, true
+		}
+		for _, letter := range target.letters() {
+			access, exists := states[rows[current.access+letter]]
+			if !exists {
+				return word + letter, target.Accepts(word + letter), true
+			}
+			next := pair{access, target.States[current.state].Transitions[letter]}
+			if _, seen := words[next]; !seen {
+				words[next] = word + letter
+				queue = append(queue, next)
+			}
+		}
+	}
+	return "", false, false
+}
+
+// newTestTable - таблица, учитель которой - MAT-сервер с языком target
+func newTestTable(t *testing.T, target *DFA) *EquivalenceTable {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/checkWord", func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]string
+		json.NewDecoder(r.Body).Decode(&request)
+		response := "0"
+		if target.Accepts(wireWord(request["word"])) {
+			response = "1"
+		}
+		json.NewEncoder(w).Encode(map[string]string{"response": response})
+	})
+	mux.HandleFunc("/check-word-batch", func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Words []string `json:"wordList"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		answers := make([]bool, len(request.Words))
+		for i, word := range request.Words {
+			answers[i] = target.Accepts(wireWord(word))
+		}
+		json.NewEncoder(w).Encode(map[string][]bool{"responseList": answers})
+	})
+	mux.HandleFunc("/checkTable", func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]string
+		json.NewDecoder(r.Body).Decode(&request)
+		word, belongs, found := tableCounterexample(target, request)
+		if !found {
+			json.NewEncoder(w).Encode(map[string]interface{}{"response": "true"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"response": orEpsilon(word, "ε"), "type": belongs})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	teacher := &Teacher{Mode: "automatic", Server: host, Port: port, WireEpsilon: "ε", ctx: context.Background()}
+	return NewEquivalenceTable(teacher, "", nil, nil)
+}
+
+// learnerTargets - языки для проверки минимальности гипотез
+func learnerTargets() map[string]*DFA {
+	targets := map[string]*DFA{
+		"third from end is a":  nthFromEndIsA(3),
+		"fourth from end is a": nthFromEndIsA(4),
+	}
+	for seed := int64(1); seed <= 12; seed++ {
+		random := rand.New(rand.NewSource(seed))
+		targets[fmt.Sprintf("random %d", seed)] = randomDFA(random, 5+random.Intn(4), "abc")
+	}
+	return targets
+}
+
+// checkMinimalHypothesis - гипотеза задаёт язык target и минимальна
+func checkMinimalHypothesis(t *testing.T, target, hypothesis *DFA) {
+	t.Helper()
+	if equivalent, witness := hypothesis.IsEquivalent(target); !equivalent {
+		t.Fatalf("гипотеза расходится с языком на слове %q", witness)
+	}
+	if got, want := len(hypothesis.States), len(target.Minimize().States); got != want {
+		t.Fatalf("состояний в гипотезе %d, в минимальном автомате %d", got, want)
+	}
+}
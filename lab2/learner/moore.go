@@ -0,0 +1,200 @@
+package learner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// MooreState - состояние машины Мура: выход определяется состоянием
+type MooreState struct {
+	Access      string         `json:"access"`
+	Output      string         `json:"output"`
+	Transitions map[string]int `json:"transitions"`
+}
+
+// MooreMachine - машина Мура; выход слова - выход состояния, в котором оно заканчивается
+type MooreMachine struct {
+	Type     string       `json:"type"`
+	Alphabet string       `json:"alphabet"`
+	Epsilon  string       `json:"epsilon"`
+	Start    int          `json:"start"`
+	States   []MooreState `json:"states"`
+}
+
+// MealyTransition - переход машины Мили с выходом
+type MealyTransition struct {
+	Target int    `json:"target"`
+	Output string `json:"output"`
+}
+
+// MealyState - состояние машины Мили
+type MealyState struct {
+	Access      string                     `json:"access"`
+	Transitions map[string]MealyTransition `json:"transitions"`
+}
+
+// MealyMachine - машина Мили: выход выдаётся на каждом переходе
+type MealyMachine struct {
+	Type     string       `json:"type"`
+	Alphabet string       `json:"alphabet"`
+	Epsilon  string       `json:"epsilon"`
+	Start    int          `json:"start"`
+	States   []MealyState `json:"states"`
+}
+
+// Output - выход машины Мура на слове
+func (machine *MooreMachine) Output(word string) string {
+	if word == machine.Epsilon {
+		word = ""
+	}
+	state := machine.Start
+	for _, letter := range word {
+		next, ok := machine.States[state].Transitions[string(letter)]
+		if !ok {
+			return ""
+		}
+		state = next
+	}
+	return machine.States[state].Output
+}
+
+// ToMealy - машина Мили с теми же состояниями: выход перехода - выход состояния, в которое он ведёт
+// Выход пустого слова при этом теряется
+func (machine *MooreMachine) ToMealy() *MealyMachine {
+	mealy := &MealyMachine{
+		Type:     "mealy",
+		Alphabet: machine.Alphabet,
+		Epsilon:  machine.Epsilon,
+		Start:    machine.Start,
+	}
+	for _, state := range machine.States {
+		mealyState := MealyState{Access: state.Access, Transitions: make(map[string]MealyTransition)}
+		for letter, target := range state.Transitions {
+			mealyState.Transitions[letter] = MealyTransition{Target: target, Output: machine.States[target].Output}
+		}
+		mealy.States = append(mealy.States, mealyState)
+	}
+	return mealy
+}
+
+// Outputs - последовательность выходов машины Мили на слове
+func (machine *MealyMachine) Outputs(word string) []string {
+	if word == machine.Epsilon {
+		word = ""
+	}
+	var outputs []string
+	state := machine.Start
+	for _, letter := range word {
+		transition, ok := machine.States[state].Transitions[string(letter)]
+		if !ok {
+			break
+		}
+		outputs = append(outputs, transition.Output)
+		state = transition.Target
+	}
+	return outputs
+}
+
+// DOT - описание машины Мура для Graphviz
+func (machine *MooreMachine) DOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph moore {\n\trankdir=LR;\n\t__start [shape=point];\n")
+	fmt.Fprintf(&sb, "\t__start -> q%d;\n", machine.Start)
+	for i, state := range machine.States {
+		fmt.Fprintf(&sb, "\tq%d [shape=circle, label=\"q%d / %s\"];\n", i, i, state.Output)
+	}
+	for i, state := range machine.States {
+		for _, letter := range machine.Alphabet {
+			if target, ok := state.Transitions[string(letter)]; ok {
+				fmt.Fprintf(&sb, "\tq%d -> q%d [label=%q];\n", i, target, string(letter))
+			}
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// DOT - описание машины Мили для Graphviz
+func (machine *MealyMachine) DOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph mealy {\n\trankdir=LR;\n\t__start [shape=point];\n")
+	fmt.Fprintf(&sb, "\t__start -> q%d;\n", machine.Start)
+	for i, state := range machine.States {
+		for _, letter := range machine.Alphabet {
+			if transition, ok := state.Transitions[string(letter)]; ok {
+				fmt.Fprintf(&sb, "\tq%d -> q%d [label=%q];\n", i, transition.Target, string(letter)+" / "+transition.Output)
+			}
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// DOT - описание автомата для Graphviz
+func (dfa *DFA) DOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph dfa {\n\trankdir=LR;\n\t__start [shape=point];\n")
+	fmt.Fprintf(&sb, "\t__start -> q%d;\n", dfa.Start)
+	for i, state := range dfa.States {
+		shape := "circle"
+		if state.Accepting {
+			shape = "doublecircle"
+		}
+		fmt.Fprintf(&sb, "\tq%d [shape=%s];\n", i, shape)
+	}
+	for i, state := range dfa.States {
+		for _, letter := range dfa.letters() {
+			if target, ok := state.Transitions[letter]; ok {
+				fmt.Fprintf(&sb, "\tq%d -> q%d [label=%q];\n", i, target, letter)
+			}
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// SaveJSON - сохранение автомата или машины в файл в формате JSON
+func SaveJSON(value interface{}, path string) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("ошибка при сериализации автомата: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("ошибка при записи автомата: %v", err)
+	}
+	return nil
+}
+
+// LoadMachine - загрузка сохранённого автомата: ДКА, машины Мура или машины Мили
+// Возвращается ровно один ненулевой указатель
+func LoadMachine(path string) (*DFA, *MooreMachine, *MealyMachine, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("ошибка при открытии файла автомата: %v", err)
+	}
+	var header struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, nil, nil, fmt.Errorf("ошибка при разборе автомата: %v", err)
+	}
+
+	switch header.Type {
+	case "moore":
+		var machine MooreMachine
+		if err := json.Unmarshal(data, &machine); err != nil {
+			return nil, nil, nil, fmt.Errorf("ошибка при разборе автомата: %v", err)
+		}
+		return nil, &machine, nil, nil
+	case "mealy":
+		var machine MealyMachine
+		if err := json.Unmarshal(data, &machine); err != nil {
+			return nil, nil, nil, fmt.Errorf("ошибка при разборе автомата: %v", err)
+		}
+		return nil, nil, &machine, nil
+	}
+	dfa, err := LoadDFA(path)
+	return dfa, nil, nil, err
+}
@@ -0,0 +1,33 @@
+package learner
+
+// LearnMoore - обучение машины Мура по L*: в ячейках таблицы выходы учителя
+// Эквивалентность проверяет функция equivalence, возвращающая контрпример; epsilon - запись пустого слова
+func LearnMoore(oracle OutputOracle, alphabet, epsilon string, equivalence func(*MooreMachine) (string, bool, error)) (*MooreMachine, error) {
+	ot := NewOutputTable(oracle, epsilon)
+	ot.addExtensions(alphabet)
+
+	for {
+		if err := ot.Fill(); err != nil {
+			return nil, err
+		}
+		if ot.CompleteTable(alphabet) {
+			continue
+		}
+		if ot.InconsistencyTable(alphabet) {
+			continue
+		}
+
+		machine := ot.BuildMoore(alphabet)
+		counterexample, found, err := equivalence(machine)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return machine, nil
+		}
+		letters := []rune(counterexample)
+		for i := range letters {
+			ot.AddSuffix(string(letters[i:]))
+		}
+	}
+}
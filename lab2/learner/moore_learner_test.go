@@ -0,0 +1,60 @@
+package learner
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// outputFunc - учитель с выходами, заданными функцией от слова
+type outputFunc func(word string) string
+
+// Outputs - выходы всех слов
+func (fn outputFunc) Outputs(words []string) ([]string, error) {
+	outputs := make([]string, len(words))
+	for i, word := range words {
+		outputs[i] = fn(word)
+	}
+	return outputs, nil
+}
+
+// countAModFive - выход "b" у слов, оканчивающихся на b, у остальных - число букв a по модулю 5;
+// минимальная машина Мура - 10 состояний с 6 различными выходами
+func countAModFive(word string) string {
+	if strings.HasSuffix(word, "b") {
+		return "b"
+	}
+	return fmt.Sprint(strings.Count(word, "a") % 5)
+}
+
+func TestLearnMooreManyOutputs(t *testing.T) {
+	words := allWords("ab", 8)
+	// Контрпример - кратчайшее слово, на котором выход машины неверен
+	equivalence := func(machine *MooreMachine) (string, bool, error) {
+		for _, word := range words {
+			if machine.Output(word) != countAModFive(word) {
+				return word, true, nil
+			}
+		}
+		return "", false, nil
+	}
+
+	machine, err := LearnMoore(outputFunc(countAModFive), "ab", "eps", equivalence)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(machine.States) != 10 {
+		t.Fatalf("состояний %d, ожидалось 10", len(machine.States))
+	}
+	if machine.Epsilon != "eps" || machine.States[machine.Start].Access != "eps" {
+		t.Fatalf("пустое слово записано как %q, строка доступа начального состояния %q", machine.Epsilon, machine.States[machine.Start].Access)
+	}
+	for _, state := range machine.States {
+		if state.Access != "eps" && strings.Contains(state.Access, "eps") {
+			t.Fatalf("в строке доступа %q осталась запись пустого слова", state.Access)
+		}
+	}
+	if _, found, _ := equivalence(machine); found {
+		t.Fatal("машина расходится с учителем")
+	}
+}
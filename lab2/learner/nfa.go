@@ -0,0 +1,124 @@
+package learner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// NFAState - состояние недетерминированного автомата
+type NFAState struct {
+	Access      string           `json:"access"`
+	Accepting   bool             `json:"accepting"`
+	Transitions map[string][]int `json:"transitions"`
+}
+
+// NFA - недетерминированный конечный автомат
+type NFA struct {
+	Alphabet string     `json:"alphabet"`
+	Epsilon  string     `json:"epsilon"`
+	Initial  []int      `json:"initial"`
+	States   []NFAState `json:"states"`
+	// Имена символов и их разделитель, как у ДКА: в памяти - внутренние руны, в файле - имена
+	Symbols   []string `json:"symbols,omitempty"`
+	Separator string   `json:"separator,omitempty"`
+}
+
+// subsetKey - ключ множества состояний
+func subsetKey(states []int) string {
+	return fmt.Sprint(states)
+}
+
+// step - множество состояний после чтения буквы
+func (nfa *NFA) step(states []int, letter string) []int {
+	seen := make(map[int]bool)
+	var result []int
+	for _, state := range states {
+		for _, target := range nfa.States[state].Transitions[letter] {
+			if !seen[target] {
+				seen[target] = true
+				result = append(result, target)
+			}
+		}
+	}
+	sort.Ints(result)
+	return result
+}
+
+// Accepts - принадлежит ли слово языку автомата
+func (nfa *NFA) Accepts(word string) bool {
+	if word == nfa.Epsilon {
+		word = ""
+	}
+	current := append([]int{}, nfa.Initial...)
+	sort.Ints(current)
+	for _, letter := range word {
+		current = nfa.step(current, string(letter))
+	}
+	for _, state := range current {
+		if nfa.States[state].Accepting {
+			return true
+		}
+	}
+	return false
+}
+
+// Determinize - построение детерминированного автомата по подмножествам
+// Строятся только достижимые подмножества, пустое подмножество становится тупиковым состоянием
+func (nfa *NFA) Determinize() *DFA {
+	start := append([]int{}, nfa.Initial...)
+	sort.Ints(start)
+
+	dfa := &DFA{Alphabet: nfa.Alphabet, Epsilon: nfa.Epsilon, Start: 0}
+	index := map[string]int{subsetKey(start): 0}
+	subsets := [][]int{start}
+	access := []string{""}
+	for i := 0; i < len(subsets); i++ {
+		state := DFAState{
+			Access:      orEpsilon(access[i], nfa.Epsilon),
+			Transitions: make(map[string]int),
+		}
+		for _, member := range subsets[i] {
+			if nfa.States[member].Accepting {
+				state.Accepting = true
+			}
+		}
+		for _, letter := range nfa.Alphabet {
+			next := nfa.step(subsets[i], string(letter))
+			key := subsetKey(next)
+			target, exists := index[key]
+			if !exists {
+				target = len(subsets)
+				index[key] = target
+				subsets = append(subsets, next)
+				access = append(access, access[i]+string(letter))
+			}
+			state.Transitions[string(letter)] = target
+		}
+		dfa.States = append(dfa.States, state)
+	}
+	return dfa
+}
+
+// String - текстовое описание автомата
+func (nfa *NFA) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Начальные состояния: %v\n", nfa.Initial)
+	for i, state := range nfa.States {
+		marker := ""
+		if state.Accepting {
+			marker = " (F)"
+		}
+		fmt.Fprintf(&sb, "q%d [%s]%s:", i, state.Access, marker)
+		for _, letter := range nfa.Alphabet {
+			fmt.Fprintf(&sb, " %c->%v", letter, state.Transitions[string(letter)])
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// SaveNFA - сохранение автомата в файл; многобуквенные символы записываются именами
+func SaveNFA(nfa *NFA, path string) error {
+	return SaveJSON(encodeNFA(nfa), path)
+}
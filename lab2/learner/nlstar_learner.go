@@ -0,0 +1,51 @@
+package learner
+
+// LearnNLStar - обучение алгоритмом NL*
+// Гипотеза - резидуальный недетерминированный автомат, который для языков с большим
+// минимальным ДКА заметно меньше. MAT принимает только ДКА, поэтому перед запросом
+// эквивалентности гипотеза детерминизируется построением подмножеств.
+// Возвращаются угаданный резидуальный NFA и его детерминизация
+func LearnNLStar(et *EquivalenceTable, alphabet string) (*NFA, *DFA, error) {
+	et.AddSuffix(et.Epsilon)
+	addMainPrefix(et, et.Epsilon, alphabet)
+
+	for {
+		et.roundStart()
+		et.FillUnknown()
+		suffixes := et.sortedSuffixes()
+
+		if prefix, closed := et.RFSAClosedness(suffixes); !closed {
+			addMainPrefix(et, prefix, alphabet)
+			continue
+		}
+		if suffix, consistent := et.RFSAConsistency(alphabet, suffixes); !consistent {
+			et.AddSuffix(et.Word(suffix))
+			continue
+		}
+
+		nfa := et.BuildNFA(alphabet)
+		hypothesis := nfa.Determinize()
+		counterexample, done, err := askForHypothesis(et, hypothesis)
+		if err != nil {
+			return nil, nil, err
+		}
+		if done {
+			return nfa, hypothesis, nil
+		}
+
+		// Как и в L*, добавляем все суффиксы контрпримера
+		letters := []rune(counterexample)
+		for i := range letters {
+			et.AddSuffix(string(letters[i:]))
+		}
+	}
+}
+
+// addMainPrefix - перенос префикса в главную часть и добавление его продолжений на буквы
+func addMainPrefix(et *EquivalenceTable, prefix string, alphabet string) {
+	et.AddPrefix(Prefix{Value: prefix, IsMain: false})
+	et.promote(prefix)
+	for _, letter := range alphabet {
+		et.AddPrefix(Prefix{Value: et.Word(prefix, string(letter)), IsMain: false})
+	}
+}
@@ -0,0 +1,22 @@
+package learner
+
+import (
+	"testing"
+)
+
+func TestLearnNLStarReturnsResidualNFA(t *testing.T) {
+	for name, target := range learnerTargets() {
+		t.Run(name, func(t *testing.T) {
+			nfa, hypothesis, err := LearnNLStar(newTestTable(t, target), target.Alphabet)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if equivalent, witness := nfa.Determinize().IsEquivalent(target); !equivalent {
+				t.Fatalf("NFA расходится с языком на слове %q", witness)
+			}
+			if equivalent, witness := hypothesis.IsEquivalent(target); !equivalent {
+				t.Fatalf("гипотеза расходится с языком на слове %q", witness)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package learner
+
+// PrefixSet - префиксы таблицы в порядке добавления с индексом по значению
+// Обход в порядке добавления делает последовательность вопросов одинаковой от запуска к запуску
+type PrefixSet struct {
+	items []Prefix
+	index map[string]int
+}
+
+// NewPrefixSet - набор из заданных префиксов
+func NewPrefixSet(prefixes ...Prefix) *PrefixSet {
+	set := &PrefixSet{index: make(map[string]int)}
+	for _, prefix := range prefixes {
+		set.Add(prefix)
+	}
+	return set
+}
+
+// Get - префикс по значению
+func (set *PrefixSet) Get(value string) (Prefix, bool) {
+	i, exists := set.index[value]
+	if !exists {
+		return Prefix{}, false
+	}
+	return set.items[i], true
+}
+
+// Add - добавление префикса в конец; false, если он уже есть
+func (set *PrefixSet) Add(prefix Prefix) bool {
+	if _, exists := set.index[prefix.Value]; exists {
+		return false
+	}
+	set.index[prefix.Value] = len(set.items)
+	set.items = append(set.items, prefix)
+	return true
+}
+
+// Set - замена префикса на его месте или добавление в конец
+func (set *PrefixSet) Set(prefix Prefix) {
+	if i, exists := set.index[prefix.Value]; exists {
+		set.items[i] = prefix
+		return
+	}
+	set.Add(prefix)
+}
+
+// All - префиксы в порядке добавления
+func (set *PrefixSet) All() []Prefix {
+	return set.items
+}
+
+// Len - число префиксов
+func (set *PrefixSet) Len() int {
+	return len(set.items)
+}
+
+// SuffixSet - суффиксы таблицы в порядке добавления; номер суффикса - номер столбца таблицы
+type SuffixSet struct {
+	items []string
+	index map[string]int
+}
+
+// NewSuffixSet - набор из заданных суффиксов
+func NewSuffixSet(suffixes ...string) *SuffixSet {
+	set := &SuffixSet{index: make(map[string]int)}
+	for _, suffix := range suffixes {
+		set.Add(suffix)
+	}
+	return set
+}
+
+// Index - номер суффикса
+func (set *SuffixSet) Index(suffix string) (int, bool) {
+	i, exists := set.index[suffix]
+	return i, exists
+}
+
+// Has - есть ли суффикс в наборе
+func (set *SuffixSet) Has(suffix string) bool {
+	_, exists := set.index[suffix]
+	return exists
+}
+
+// Add - добавление суффикса в конец; false, если он уже есть
+func (set *SuffixSet) Add(suffix string) bool {
+	if set.Has(suffix) {
+		return false
+	}
+	set.index[suffix] = len(set.items)
+	set.items = append(set.items, suffix)
+	return true
+}
+
+// All - суффиксы в порядке добавления
+func (set *SuffixSet) All() []string {
+	return set.items
+}
+
+// Len - число суффиксов
+func (set *SuffixSet) Len() int {
+	return len(set.items)
+}
@@ -0,0 +1,148 @@
+package learner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+)
+
+// OutputOracle - учитель, возвращающий для слова выходной символ (например, класс лексемы)
+type OutputOracle interface {
+	Outputs(words []string) ([]string, error)
+}
+
+// httpOutputOracle - выходы слов от MAT-сервера
+type httpOutputOracle struct {
+	server string
+	port   string
+}
+
+// Outputs - пакетный запрос выходов: POST /check-word-output-batch
+func (oracle *httpOutputOracle) Outputs(words []string) ([]string, error) {
+	url := fmt.Sprintf("http://%s:%s/check-word-output-batch", oracle.server, oracle.port)
+
+	requestBody, err := json.Marshal(map[string][]string{"wordList": words})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при формировании тела запроса: %v", err)
+	}
+	resp, err := http.Post(url, "application/json", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при отправке запроса: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Outputs []string `json:"responseList"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("ошибка при декодировании JSON: %v", err)
+	}
+	if len(response.Outputs) != len(words) {
+		return nil, fmt.Errorf("некорректное количество ответов: ожидалось %d, получено %d", len(words), len(response.Outputs))
+	}
+	return response.Outputs, nil
+}
+
+// manualOutputOracle - выходы слов вводит пользователь; пустое слово выводится как epsilon
+type manualOutputOracle struct {
+	epsilon string
+}
+
+// Outputs - вопрос пользователю для каждого слова
+func (oracle *manualOutputOracle) Outputs(words []string) ([]string, error) {
+	outputs := make([]string, len(words))
+	for i, word := range words {
+		fmt.Printf("Выход для слова '%s': ", orEpsilon(word, oracle.epsilon))
+		fmt.Scanln(&outputs[i])
+	}
+	return outputs, nil
+}
+
+// cachedOutputOracle - учитель со словарём уже известных ответов
+// Если задан teacher, заданные вопросы учитываются в его счётчиках и сообщаются подписчикам,
+// пустое слово - в записи epsilon
+type cachedOutputOracle struct {
+	oracle  OutputOracle
+	teacher *Teacher
+	epsilon string
+	Words   map[string]string
+}
+
+// Outputs - известные ответы берутся из словаря, остальные спрашиваются одним пакетом
+func (cache *cachedOutputOracle) Outputs(words []string) ([]string, error) {
+	var unknown []string
+	seen := make(map[string]bool)
+	for _, word := range words {
+		if _, exists := cache.Words[word]; !exists && !seen[word] {
+			seen[word] = true
+			unknown = append(unknown, word)
+		}
+	}
+	if len(unknown) > 0 {
+		outputs, err := cache.oracle.Outputs(unknown)
+		if err != nil {
+			return nil, err
+		}
+		for i, word := range unknown {
+			cache.Words[word] = outputs[i]
+		}
+		if cache.teacher != nil {
+			cache.teacher.Stats.MembershipQueries += len(unknown)
+			for i, word := range unknown {
+				cache.teacher.Hooks.outputQuery(orEpsilon(word, cache.epsilon), outputs[i])
+			}
+		}
+	}
+
+	result := make([]string, len(words))
+	for i, word := range words {
+		result[i] = cache.Words[word]
+	}
+	return result, nil
+}
+
+// FindOutputCounterexample - поиск контрпримера к гипотезе случайными словами
+// MAT не проверяет машины с выходами, поэтому эквивалентность приближается тестированием
+func FindOutputCounterexample(oracle OutputOracle, machine *MooreMachine, tests, maxLength int) (string, bool, error) {
+	random := rand.New(rand.NewSource(int64(len(machine.States))))
+	letters := []rune(machine.Alphabet)
+	words := make([]string, 0, tests)
+	for i := 0; i < tests; i++ {
+		length := random.Intn(maxLength + 1)
+		word := make([]rune, length)
+		for k := range word {
+			word[k] = letters[random.Intn(len(letters))]
+		}
+		words = append(words, string(word))
+	}
+
+	outputs, err := oracle.Outputs(words)
+	if err != nil {
+		return "", false, err
+	}
+	// Из найденных контрпримеров берём кратчайший
+	counterexample, found := "", false
+	for i, word := range words {
+		if machine.Output(word) != outputs[i] && (!found || len(word) < len(counterexample)) {
+			counterexample, found = word, true
+		}
+	}
+	return counterexample, found, nil
+}
+
+// manualOutputEquivalence - проверку гипотезы выполняет пользователь; пустой контрпример
+// вводится в записи пустого слова машины
+func manualOutputEquivalence(machine *MooreMachine) (string, bool, error) {
+	fmt.Print(machine.DOT())
+	var response string
+	fmt.Print("Верна ли машина выше? (true/false): ")
+	fmt.Scanln(&response)
+	if response == "true" {
+		return "", false, nil
+	}
+	fmt.Print("Введите контрпример: ")
+	fmt.Scanln(&response)
+	return trimEpsilon(response, machine.Epsilon), true, nil
+}
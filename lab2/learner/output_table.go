@@ -0,0 +1,185 @@
+package learner
+
+// OutputTable - таблица классов эквивалентности, в ячейках которой выходы учителя, а не '+'/'-'
+// Строки те же, что у EquivalenceTable: значение ячейки - номер выхода учителя, поэтому классы
+// строк, полнота и непротиворечивость проверяются общим с ней кодом (closeRows, findEqualMainRows)
+type OutputTable struct {
+	Prefixes *PrefixSet           // Префиксы в порядке добавления
+	Suffixes *SuffixSet           // Суффиксы в порядке добавления, номер суффикса - номер столбца
+	Table    map[string]*TableRow // Строки таблицы: префикс -> номера выходов по номерам столбцов
+	Epsilon  string               // Запись пустого слова в префиксах и суффиксах
+	outputs  []string             // Различные выходы учителя: ячейка со значением c - выход outputs[c-1]
+	codes    map[string]Cell      // Выход -> значение ячейки
+	oracle   OutputOracle         // Учитель со словарём известных слов
+}
+
+// NewOutputTable - таблица с пустым префиксом и пустым суффиксом; пустой epsilon означает ε
+func NewOutputTable(oracle OutputOracle, epsilon string) *OutputTable {
+	if epsilon == "" {
+		epsilon = defaultEpsilon
+	}
+	ot := &OutputTable{
+		Prefixes: NewPrefixSet(),
+		Suffixes: NewSuffixSet(),
+		Table:    make(map[string]*TableRow),
+		Epsilon:  epsilon,
+		codes:    make(map[string]Cell),
+		oracle:   oracle,
+	}
+	ot.AddSuffix(epsilon)
+	ot.AddPrefix(Prefix{Value: epsilon, IsMain: true})
+	return ot
+}
+
+// word - слово из частей в записи таблицы, как EquivalenceTable.Word
+func (ot *OutputTable) word(parts ...string) string {
+	var word string
+	for _, part := range parts {
+		word += trimEpsilon(part, ot.Epsilon)
+	}
+	return orEpsilon(word, ot.Epsilon)
+}
+
+// cellOf - значение ячейки для выхода; новый выход получает следующий номер
+func (ot *OutputTable) cellOf(output string) Cell {
+	if cell, exists := ot.codes[output]; exists {
+		return cell
+	}
+	ot.outputs = append(ot.outputs, output)
+	ot.codes[output] = Cell(len(ot.outputs))
+	return ot.codes[output]
+}
+
+// Output - выход учителя в ячейке; пустая строка, если ячейка не заполнена
+func (ot *OutputTable) Output(prefix, suffix string) string {
+	row, exists := ot.Table[prefix]
+	if !exists {
+		return ""
+	}
+	column, exists := ot.Suffixes.Index(suffix)
+	if !exists {
+		return ""
+	}
+	cell := row.Get(column)
+	if cell == CellUnknown {
+		return ""
+	}
+	return ot.outputs[cell-1]
+}
+
+// AddPrefix - Добавление нового префикса
+func (ot *OutputTable) AddPrefix(newPrefix Prefix) bool {
+	if !ot.Prefixes.Add(newPrefix) {
+		return false
+	}
+	ot.Table[newPrefix.Value] = &TableRow{}
+	return true
+}
+
+// AddSuffix - Добавление нового суффикса
+func (ot *OutputTable) AddSuffix(newSuffix string) bool {
+	return ot.Suffixes.Add(newSuffix)
+}
+
+// Fill - заполнение пустых ячеек ответами учителя
+func (ot *OutputTable) Fill() error {
+	var cells []Pair
+	var words []string
+	for _, prefix := range ot.Prefixes.All() {
+		for column, suffix := range ot.Suffixes.All() {
+			if ot.Table[prefix.Value].Get(column) == CellUnknown {
+				cells = append(cells, Pair{First: prefix.Value, Second: suffix})
+				words = append(words, trimEpsilon(ot.word(prefix.Value, suffix), ot.Epsilon))
+			}
+		}
+	}
+	if len(words) == 0 {
+		return nil
+	}
+	outputs, err := ot.oracle.Outputs(words)
+	if err != nil {
+		return err
+	}
+	for i, cell := range cells {
+		column, _ := ot.Suffixes.Index(cell.Second)
+		ot.Table[cell.First].Set(column, ot.cellOf(outputs[i]))
+	}
+	return nil
+}
+
+// CompleteTable - Приведение таблицы к полному виду
+// Возвращает true, если какой-то префикс был перенесён в главную часть
+func (ot *OutputTable) CompleteTable(alphabet string) bool {
+	changed := false
+	closeRows(ot.Prefixes, ot.Table, func(prefix string) {
+		ot.Prefixes.Set(Prefix{Value: prefix, IsMain: true})
+		changed = true
+	}, nil)
+	ot.addExtensions(alphabet)
+	return changed
+}
+
+// addExtensions - продолжения главных префиксов на все буквы
+func (ot *OutputTable) addExtensions(alphabet string) {
+	for _, prefix := range ot.Prefixes.All() {
+		if !prefix.IsMain {
+			continue
+		}
+		for _, letter := range alphabet {
+			ot.AddPrefix(Prefix{Value: ot.word(prefix.Value, string(letter)), IsMain: false})
+		}
+	}
+}
+
+// InconsistencyTable - Проверка на противоречивость и исправление
+// Вызывается для заполненной таблицы: строки продолжений сравниваются целиком
+func (ot *OutputTable) InconsistencyTable(alphabet string) bool {
+	return findEqualMainRows(ot.Prefixes, ot.Table, func(prefix1, prefix2 string) bool {
+		for _, letter := range alphabet {
+			row1, ok1 := ot.Table[ot.word(prefix1, string(letter))]
+			row2, ok2 := ot.Table[ot.word(prefix2, string(letter))]
+			if !ok1 || !ok2 || row1.Equal(row2) {
+				continue
+			}
+			for column, suffix := range ot.Suffixes.All() {
+				if row1.Get(column) != row2.Get(column) {
+					ot.AddSuffix(ot.word(string(letter), suffix))
+					return true
+				}
+			}
+		}
+		return false
+	})
+}
+
+// BuildMoore - машина Мура по главной части таблицы
+func (ot *OutputTable) BuildMoore(alphabet string) *MooreMachine {
+	prefixes := (&EquivalenceTable{Prefixes: ot.Prefixes, Epsilon: ot.Epsilon}).sortedMainPrefixes()
+
+	machine := &MooreMachine{Type: "moore", Alphabet: alphabet, Epsilon: ot.Epsilon, Start: 0}
+	classes := newRowClasses(ot.Table)
+	states := make(map[string]int)
+	for _, prefix := range prefixes {
+		if classes.Add(prefix) != prefix {
+			continue
+		}
+		states[prefix] = len(machine.States)
+		machine.States = append(machine.States, MooreState{
+			Access:      prefix,
+			Output:      ot.Output(prefix, ot.Epsilon),
+			Transitions: make(map[string]int),
+		})
+	}
+	for i := range machine.States {
+		for _, letter := range alphabet {
+			next := ot.word(machine.States[i].Access, string(letter))
+			if _, exists := ot.Table[next]; !exists {
+				continue
+			}
+			if representative, exists := classes.Find(next); exists {
+				machine.States[i].Transitions[string(letter)] = states[representative]
+			}
+		}
+	}
+	return machine
+}
@@ -0,0 +1,223 @@
+package learner
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Метки состояний дерева префиксов
+const (
+	labelUnknown = 0
+	labelAccept  = 1
+	labelReject  = -1
+)
+
+// mergeAutomaton - дерево префиксов выборки, в котором склеиваются состояния
+type mergeAutomaton struct {
+	letters []rune
+	access  []string // Строки доступа в дереве префиксов, по возрастанию длины
+	trans   [][]int  // trans[состояние][номер символа], -1 - перехода нет
+	label   []int
+}
+
+// buildPTA - дерево префиксов: состояния нумеруются префиксами слов в порядке длины
+func buildPTA(samples map[string]bool, alphabet string) (*mergeAutomaton, error) {
+	pta := &mergeAutomaton{letters: []rune(alphabet)}
+	letterIndex := make(map[rune]int)
+	for i, letter := range pta.letters {
+		letterIndex[letter] = i
+	}
+
+	prefixes := make(map[string]bool)
+	for word := range samples {
+		letters := []rune(word)
+		for i := 0; i <= len(letters); i++ {
+			prefixes[string(letters[:i])] = true
+		}
+	}
+
+	index := make(map[string]int)
+	for _, prefix := range sortedSampleWords(prefixes) {
+		state := len(pta.access)
+		index[prefix] = state
+		pta.access = append(pta.access, prefix)
+		pta.trans = append(pta.trans, make([]int, len(pta.letters)))
+		for i := range pta.trans[state] {
+			pta.trans[state][i] = -1
+		}
+		pta.label = append(pta.label, labelUnknown)
+
+		letters := []rune(prefix)
+		if len(letters) == 0 {
+			continue
+		}
+		last, exists := letterIndex[letters[len(letters)-1]]
+		if !exists {
+			return nil, fmt.Errorf("символ %c слова %s не входит в алфавит", letters[len(letters)-1], prefix)
+		}
+		pta.trans[index[string(letters[:len(letters)-1])]][last] = state
+	}
+
+	for word, belongs := range samples {
+		if belongs {
+			pta.label[index[word]] = labelAccept
+		} else {
+			pta.label[index[word]] = labelReject
+		}
+	}
+	return pta, nil
+}
+
+// clone - копия для пробной склейки
+func (automaton *mergeAutomaton) clone() *mergeAutomaton {
+	result := *automaton
+	result.trans = make([][]int, len(automaton.trans))
+	for i, row := range automaton.trans {
+		result.trans[i] = append([]int{}, row...)
+	}
+	result.label = append([]int{}, automaton.label...)
+	return &result
+}
+
+// merge - склейка синего состояния blue с красным red и свёртка поддерева blue
+// Возвращает новый автомат и число совпавших меток (оценка EDSM); false - метки противоречат
+func (automaton *mergeAutomaton) merge(red, blue int) (*mergeAutomaton, int, bool) {
+	result := automaton.clone()
+	// После прежних свёрток в blue ведёт уже не ребро дерева префиксов, а переход,
+	// скопированный в красное состояние, поэтому перенаправляются все переходы в blue
+	for _, row := range result.trans {
+		for letter, target := range row {
+			if target == blue {
+				row[letter] = red
+			}
+		}
+	}
+	score := 0
+	if !result.fold(red, blue, &score) {
+		return nil, 0, false
+	}
+	return result, score, true
+}
+
+// fold - свёртка поддерева blue в состояние red
+func (automaton *mergeAutomaton) fold(red, blue int, score *int) bool {
+	if automaton.label[blue] != labelUnknown {
+		switch automaton.label[red] {
+		case labelUnknown:
+			automaton.label[red] = automaton.label[blue]
+		case automaton.label[blue]:
+			*score++
+		default:
+			return false
+		}
+	}
+	for letter, child := range automaton.trans[blue] {
+		if child < 0 {
+			continue
+		}
+		if target := automaton.trans[red][letter]; target >= 0 {
+			if !automaton.fold(target, child, score) {
+				return false
+			}
+		} else {
+			automaton.trans[red][letter] = child
+		}
+	}
+	return true
+}
+
+// blues - состояния, в которые ведут переходы из красных, но сами не красные, по возрастанию
+func (automaton *mergeAutomaton) blues(red map[int]bool) []int {
+	seen := make(map[int]bool)
+	var result []int
+	for state := range red {
+		for _, target := range automaton.trans[state] {
+			if target >= 0 && !red[target] && !seen[target] {
+				seen[target] = true
+				result = append(result, target)
+			}
+		}
+	}
+	sort.Ints(result)
+	return result
+}
+
+// LearnPassive - пассивное обучение по размеченной выборке методом rpni или edsm
+// RPNI склеивает первое синее состояние с первым подходящим красным,
+// EDSM выбирает склейку с наибольшим числом совпавших меток; epsilon - запись пустого слова в автомате
+func LearnPassive(samples map[string]bool, alphabet, method, epsilon string) (*DFA, error) {
+	if method != "rpni" && method != "edsm" {
+		return nil, fmt.Errorf("неизвестный метод пассивного обучения: %s", method)
+	}
+	automaton, err := buildPTA(samples, alphabet)
+	if err != nil {
+		return nil, err
+	}
+
+	reds := []int{0}
+	red := map[int]bool{0: true}
+	for {
+		blues := automaton.blues(red)
+		if len(blues) == 0 {
+			break
+		}
+
+		var best *mergeAutomaton
+		bestScore := -1
+		promote := -1
+		for _, blue := range blues {
+			found := false
+			for _, candidate := range reds {
+				merged, score, ok := automaton.merge(candidate, blue)
+				if !ok {
+					continue
+				}
+				found = true
+				if score > bestScore {
+					best, bestScore = merged, score
+				}
+				if method == "rpni" {
+					break
+				}
+			}
+			if !found {
+				promote = blue
+				break
+			}
+			if method == "rpni" {
+				break
+			}
+		}
+
+		if promote >= 0 {
+			reds = append(reds, promote)
+			red[promote] = true
+			continue
+		}
+		automaton = best
+	}
+
+	// Состояния результата - красные; недостающие переходы ведут в тупик
+	index := make(map[int]int)
+	for i, state := range reds {
+		index[state] = i
+	}
+	dfa := &DFA{Alphabet: alphabet, Epsilon: epsilon, Start: 0, Words: make(map[string]bool)}
+	for _, state := range reds {
+		dfaState := DFAState{
+			Access:      orEpsilon(automaton.access[state], epsilon),
+			Accepting:   automaton.label[state] == labelAccept,
+			Transitions: make(map[string]int),
+		}
+		for letter, target := range automaton.trans[state] {
+			if target >= 0 {
+				dfaState.Transitions[string(automaton.letters[letter])] = index[target]
+			}
+		}
+		dfa.States = append(dfa.States, dfaState)
+	}
+	for word, belongs := range samples {
+		dfa.Words[orEpsilon(word, epsilon)] = belongs
+	}
+	return dfa.Minimize(), nil
+}
@@ -0,0 +1,104 @@
+package learner
+
+import (
+	"testing"
+	"time"
+)
+
+// allWords - все слова над alphabet длины не больше maxLength
+func allWords(alphabet string, maxLength int) []string {
+	words := []string{""}
+	level := []string{""}
+	for length := 1; length <= maxLength; length++ {
+		var next []string
+		for _, word := range level {
+			for _, letter := range alphabet {
+				next = append(next, word+string(letter))
+			}
+		}
+		words = append(words, next...)
+		level = next
+	}
+	return words
+}
+
+// labelWords - выборка: каждое слово размечено принадлежностью языку
+func labelWords(words []string, language func(string) bool) map[string]bool {
+	samples := make(map[string]bool, len(words))
+	for _, word := range words {
+		samples[word] = language(word)
+	}
+	return samples
+}
+
+// countLetter - число вхождений буквы в слово
+func countLetter(word string, letter rune) int {
+	count := 0
+	for _, current := range word {
+		if current == letter {
+			count++
+		}
+	}
+	return count
+}
+
+func TestLearnPassiveConsistentWithSamples(t *testing.T) {
+	evenAThreeB := func(word string) bool {
+		return countLetter(word, 'a')%2 == 0 && countLetter(word, 'b')%3 == 0
+	}
+	// Каждое девятое слово длины до 7: после первых склеек синие состояния
+	// достижимы уже не по рёбрам дерева префиксов
+	var sparse []string
+	for i, word := range allWords("ab", 7) {
+		if i%9 == 0 {
+			sparse = append(sparse, word)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		alphabet string
+		samples  map[string]bool
+	}{
+		{"ends with a", "ab", labelWords(allWords("ab", 4), func(word string) bool {
+			return len(word) > 0 && word[len(word)-1] == 'a'
+		})},
+		{"even a, b divisible by 3", "ab", labelWords(allWords("ab", 5), evenAThreeB)},
+		{"even a, b divisible by 3, sparse", "ab", labelWords(sparse, evenAThreeB)},
+		{"no aa", "abc", labelWords(allWords("abc", 3), func(word string) bool {
+			for i := 1; i < len(word); i++ {
+				if word[i-1] == 'a' && word[i] == 'a' {
+					return false
+				}
+			}
+			return true
+		})},
+	}
+
+	for _, test := range tests {
+		for _, method := range []string{"rpni", "edsm"} {
+			t.Run(test.name+"/"+method, func(t *testing.T) {
+				done := make(chan struct{})
+				var dfa *DFA
+				var err error
+				go func() {
+					dfa, err = LearnPassive(test.samples, test.alphabet, method, "ε")
+					close(done)
+				}()
+				select {
+				case <-done:
+				case <-time.After(10 * time.Second):
+					t.Fatal("обучение не завершилось за 10 секунд")
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				for word, belongs := range test.samples {
+					if accepted, _ := dfa.Run(word); accepted != belongs {
+						t.Errorf("слово %q: автомат %v, выборка %v", word, accepted, belongs)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package learner
+
+import (
+	"sort"
+)
+
+// SeedFromDFA - начальные данные из ранее угаданного автомата: строки доступа минимального
+// автомата становятся префиксами, различающие суффиксы - суффиксами, сохранённые ответы - словами
+func SeedFromDFA(dfa *DFA) *Seed {
+	minimal := dfa.Minimize()
+	seed := &Seed{Words: make(map[string]bool)}
+	for _, word := range minimal.AccessStrings() {
+		seed.Prefixes = append(seed.Prefixes, word)
+	}
+	sort.Strings(seed.Prefixes)
+	for _, suffix := range minimal.SeparatingSuffixes() {
+		seed.Suffixes = append(seed.Suffixes, trimEpsilon(suffix, minimal.Epsilon))
+	}
+	for word, belongs := range dfa.Words {
+		seed.Words[trimEpsilon(word, dfa.Epsilon)] = belongs
+	}
+	return seed
+}
+
+// Convert - начальные данные, в которых каждое слово переведено функцией convert
+// (например, из имён символов через разделитель во внутреннюю запись)
+func (seed *Seed) Convert(convert func(string) (string, error)) (*Seed, error) {
+	result := &Seed{Words: make(map[string]bool, len(seed.Words))}
+	for word, belongs := range seed.Words {
+		converted, err := convert(word)
+		if err != nil {
+			return nil, err
+		}
+		result.Words[converted] = belongs
+	}
+	convertAll := func(words []string) ([]string, error) {
+		var converted []string
+		for _, word := range words {
+			value, err := convert(word)
+			if err != nil {
+				return nil, err
+			}
+			converted = append(converted, value)
+		}
+		return converted, nil
+	}
+	var err error
+	if result.Prefixes, err = convertAll(seed.Prefixes); err != nil {
+		return nil, err
+	}
+	if result.Suffixes, err = convertAll(seed.Suffixes); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RevalidateWords - повторные вопросы учителю о словах с прежними ответами
+// Язык мог измениться, поэтому старые ответы не переносятся; возвращает слова с изменившимся ответом.
+// Слова, уже записанные в словарь, учителю не задаются, поэтому перепроверка идёт до загрузки других слов
+func (et *EquivalenceTable) RevalidateWords(words map[string]bool) []string {
+	var asked []string
+	for word := range words {
+		asked = append(asked, word)
+	}
+	sort.Strings(asked)
+	answers := et.AskForWords(asked)
+
+	var changed []string
+	for i, word := range asked {
+		if answers[i] != words[word] {
+			changed = append(changed, et.display(et.Word(word)))
+		}
+	}
+	return changed
+}
+
+// prepareTable - начальные данные таблицы из конфигурации: выборка seed_file и прежний автомат
+// previous_hypothesis. Префиксы и суффиксы переносятся, только если withTable (для lstar).
+// Сколько слов, префиксов и суффиксов загружено и какие ответы изменились - в Stats.
+// Слова прежнего автомата перепроверяются до загрузки выборки: AskForWords не задаёт учителю
+// слов, уже записанных в словарь, и совпавшие с выборкой слова иначе не были бы спрошены
+func prepareTable(et *EquivalenceTable, config *Config, withTable bool) error {
+	var previous *Seed
+	var changed []string
+	if config.PreviousHypothesis != "" {
+		dfa, err := LoadDFA(config.PreviousHypothesis)
+		if err != nil {
+			return err
+		}
+		previous, err = SeedFromDFA(dfa).Convert(func(word string) (string, error) {
+			return et.teacher.Symbols.Translate(word, dfa.SymbolTable())
+		})
+		if err != nil {
+			return err
+		}
+		changed = et.RevalidateWords(previous.Words)
+	}
+
+	if config.SeedFile != "" {
+		seed, err := LoadSeed(config.SeedFile, et.Epsilon)
+		if err != nil {
+			return err
+		}
+		if seed, err = seed.Convert(et.teacher.Symbols.Decode); err != nil {
+			return err
+		}
+		// Ответ учителя на перепроверенное слово остаётся, даже если выборка с ним расходится
+		et.SeedWords(seed.Words)
+		if withTable {
+			et.SeedTable(seed.Prefixes, seed.Suffixes)
+		}
+		stats := &et.teacher.Stats
+		stats.SeedWords, stats.SeedPrefixes, stats.SeedSuffixes = len(seed.Words), len(seed.Prefixes), len(seed.Suffixes)
+	}
+
+	if previous != nil {
+		if withTable {
+			et.SeedTable(previous.Prefixes, previous.Suffixes)
+		}
+		stats := &et.teacher.Stats
+		stats.PreviousPrefixes, stats.PreviousSuffixes = len(previous.Prefixes), len(previous.Suffixes)
+		stats.RevalidatedWords, stats.ChangedWords = len(previous.Words), changed
+	}
+	return nil
+}
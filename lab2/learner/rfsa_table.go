@@ -0,0 +1,182 @@
+package learner
+
+// Операции над таблицей для NL*: строки сравниваются не только на равенство, но и по включению,
+// а составная строка - это объединение (поэлементное ИЛИ) строго меньших строк таблицы
+
+// rowBits - строка таблицы в виде вектора принадлежности
+func (et *EquivalenceTable) rowBits(prefix string, suffixes []string) []bool {
+	row := make([]bool, len(suffixes))
+	for i, suffix := range suffixes {
+		row[i] = et.GetValue(prefix, suffix) == CellAccept
+	}
+	return row
+}
+
+// rowCovers - включение строк: small ⊑ big
+func rowCovers(small, big []bool) bool {
+	for i := range small {
+		if small[i] && !big[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// rowsEqual - равенство строк
+func rowsEqual(a, b []bool) bool {
+	return rowCovers(a, b) && rowCovers(b, a)
+}
+
+// rowPrefixes - префиксы главной и неглавной части в фиксированном порядке
+func (et *EquivalenceTable) rowPrefixes() (upper, lower []string) {
+	for _, prefix := range et.sortedPrefixes() {
+		if entry, _ := et.Prefixes.Get(prefix); entry.IsMain {
+			upper = append(upper, prefix)
+		} else {
+			lower = append(lower, prefix)
+		}
+	}
+	return upper, lower
+}
+
+// IsPrimeRow - является ли строка простой среди строк rows:
+// она не равна объединению строго меньших строк (пустая строка простой не бывает)
+func IsPrimeRow(row []bool, rows [][]bool) bool {
+	join := make([]bool, len(row))
+	for _, other := range rows {
+		if rowCovers(other, row) && !rowsEqual(other, row) {
+			for i := range other {
+				join[i] = join[i] || other[i]
+			}
+		}
+	}
+	return !rowsEqual(join, row)
+}
+
+// UpperPrimes - главные префиксы с простыми строками, по одному на каждую различную строку
+func (et *EquivalenceTable) UpperPrimes(suffixes []string) []string {
+	upper, lower := et.rowPrefixes()
+	rows := make([][]bool, 0, len(upper)+len(lower))
+	for _, prefix := range append(append([]string{}, upper...), lower...) {
+		rows = append(rows, et.rowBits(prefix, suffixes))
+	}
+
+	var primes []string
+	var primeRows [][]bool
+	for i, prefix := range upper {
+		if !IsPrimeRow(rows[i], rows) {
+			continue
+		}
+		duplicate := false
+		for _, row := range primeRows {
+			if rowsEqual(row, rows[i]) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			primes = append(primes, prefix)
+			primeRows = append(primeRows, rows[i])
+		}
+	}
+	return primes
+}
+
+// coveredJoin - объединение строк простых главных префиксов, входящих в строку row
+func (et *EquivalenceTable) coveredJoin(row []bool, primes []string, suffixes []string) []bool {
+	join := make([]bool, len(row))
+	for _, prime := range primes {
+		primeRow := et.rowBits(prime, suffixes)
+		if rowCovers(primeRow, row) {
+			for i := range primeRow {
+				join[i] = join[i] || primeRow[i]
+			}
+		}
+	}
+	return join
+}
+
+// RFSAClosedness - проверка RFSA-полноты: каждая строка неглавной части должна быть
+// объединением простых строк главной части. Возвращает неглавный префикс для переноса в главную
+func (et *EquivalenceTable) RFSAClosedness(suffixes []string) (string, bool) {
+	_, lower := et.rowPrefixes()
+	primes := et.UpperPrimes(suffixes)
+
+	var rows [][]bool
+	for _, prefix := range et.sortedPrefixes() {
+		rows = append(rows, et.rowBits(prefix, suffixes))
+	}
+
+	candidate := ""
+	for _, prefix := range lower {
+		row := et.rowBits(prefix, suffixes)
+		if rowsEqual(et.coveredJoin(row, primes, suffixes), row) {
+			continue
+		}
+		// Предпочитаем префикс с простой строкой
+		if IsPrimeRow(row, rows) {
+			return prefix, false
+		}
+		if candidate == "" {
+			candidate = prefix
+		}
+	}
+	if candidate != "" {
+		return candidate, false
+	}
+	return "", true
+}
+
+// RFSAConsistency - проверка RFSA-непротиворечивости: если r(u') ⊑ r(u), то r(u'a) ⊑ r(ua)
+// Возвращает новый суффикс a·v, устраняющий противоречие
+func (et *EquivalenceTable) RFSAConsistency(alphabet string, suffixes []string) (string, bool) {
+	upper, _ := et.rowPrefixes()
+	for _, first := range upper {
+		for _, second := range upper {
+			if first == second || !rowCovers(et.rowBits(second, suffixes), et.rowBits(first, suffixes)) {
+				continue
+			}
+			for _, letter := range alphabet {
+				big := et.rowBits(et.Word(first, string(letter)), suffixes)
+				small := et.rowBits(et.Word(second, string(letter)), suffixes)
+				for i := range suffixes {
+					if small[i] && !big[i] {
+						return et.Word(string(letter), suffixes[i]), false
+					}
+				}
+			}
+		}
+	}
+	return "", true
+}
+
+// BuildNFA - резидуальный автомат по таблице: состояния - простые строки главной части,
+// переход из r(u) по a ведёт во все простые строки, входящие в r(ua)
+func (et *EquivalenceTable) BuildNFA(alphabet string) *NFA {
+	suffixes := et.sortedSuffixes()
+	primes := et.UpperPrimes(suffixes)
+	epsilonRow := et.rowBits(et.Epsilon, suffixes)
+
+	nfa := &NFA{Alphabet: alphabet, Epsilon: et.Epsilon}
+	for i, prime := range primes {
+		row := et.rowBits(prime, suffixes)
+		state := NFAState{
+			Access:      prime,
+			Accepting:   et.GetValue(prime, et.Epsilon) == CellAccept,
+			Transitions: make(map[string][]int),
+		}
+		if rowCovers(row, epsilonRow) {
+			nfa.Initial = append(nfa.Initial, i)
+		}
+		for _, letter := range alphabet {
+			next := et.rowBits(et.Word(prime, string(letter)), suffixes)
+			for j, target := range primes {
+				if rowCovers(et.rowBits(target, suffixes), next) {
+					state.Transitions[string(letter)] = append(state.Transitions[string(letter)], j)
+				}
+			}
+		}
+		nfa.States = append(nfa.States, state)
+	}
+	return nfa
+}
@@ -0,0 +1,127 @@
+package learner
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Seed - размеченные слова и, по желанию, начальные префиксы и суффиксы таблицы
+type Seed struct {
+	Words    map[string]bool
+	Prefixes []string
+	Suffixes []string
+}
+
+// LoadSeed - чтение размеченных слов из файла
+// Строка файла - "+ слово" или "- слово"; вывод команды check ("слово<TAB>accept|reject") тоже подходит.
+// Строки "prefix слово" и "suffix слово" задают начальные префиксы и суффиксы таблицы.
+// Пустое слово записывается как epsilon, пустые строки и строки с # пропускаются
+func LoadSeed(path, epsilon string) (*Seed, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при открытии файла выборки: %v", err)
+	}
+	defer file.Close()
+
+	seed := &Seed{Words: make(map[string]bool)}
+	scanner := bufio.NewScanner(file)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var word string
+		var belongs bool
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 2 && fields[0] == "prefix":
+			seed.Prefixes = append(seed.Prefixes, trimEpsilon(fields[1], epsilon))
+			continue
+		case len(fields) == 2 && fields[0] == "suffix":
+			seed.Suffixes = append(seed.Suffixes, trimEpsilon(fields[1], epsilon))
+			continue
+		case len(fields) == 2 && (fields[0] == "+" || fields[0] == "-"):
+			word, belongs = fields[1], fields[0] == "+"
+		case len(fields) == 1 && (fields[0] == "+" || fields[0] == "-"):
+			word, belongs = "", fields[0] == "+"
+		case len(fields) >= 2 && (fields[1] == "accept" || fields[1] == "reject"):
+			word, belongs = fields[0], fields[1] == "accept"
+		default:
+			return nil, fmt.Errorf("строка %d файла выборки не разобрана: %s", number, line)
+		}
+		word = trimEpsilon(word, epsilon)
+
+		if previous, exists := seed.Words[word]; exists && previous != belongs {
+			return nil, fmt.Errorf("слово %s размечено в выборке и как +, и как -", orEpsilon(word, epsilon))
+		}
+		seed.Words[word] = belongs
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при чтении файла выборки: %v", err)
+	}
+	return seed, nil
+}
+
+// LoadSamples - размеченные слова из файла; строки prefix и suffix пропускаются
+func LoadSamples(path, epsilon string) (map[string]bool, error) {
+	seed, err := LoadSeed(path, epsilon)
+	if err != nil {
+		return nil, err
+	}
+	return seed.Words, nil
+}
+
+// SeedWords - занесение размеченных слов в словарь таблицы: учителю они больше не задаются
+func (et *EquivalenceTable) SeedWords(words map[string]bool) {
+	for word, belongs := range words {
+		et.AddWord(et.Word(word), belongs)
+	}
+}
+
+// SeedTable - начальные префиксы (в главную часть, вместе с их префиксами)
+// и суффиксы (вместе с их суффиксами), чтобы таблица оставалась замкнутой
+func (et *EquivalenceTable) SeedTable(prefixes, suffixes []string) {
+	for _, prefix := range prefixes {
+		letters := []rune(prefix)
+		for i := 0; i <= len(letters); i++ {
+			value := et.Word(string(letters[:i]))
+			if !et.AddPrefix(Prefix{Value: value, IsMain: true}) {
+				et.Prefixes.Set(Prefix{Value: value, IsMain: true})
+			}
+		}
+	}
+	for _, suffix := range suffixes {
+		letters := []rune(suffix)
+		for i := 0; i <= len(letters); i++ {
+			et.AddSuffix(et.Word(string(letters[i:])))
+		}
+	}
+}
+
+// SamplesAlphabet - символы слов выборки по возрастанию
+func SamplesAlphabet(samples map[string]bool) string {
+	var sb strings.Builder
+	for word := range samples {
+		sb.WriteString(word)
+	}
+	return string(orderedAlphabet(sb.String()))
+}
+
+// sortedSampleWords - слова выборки в порядке длины, затем лексикографически
+func sortedSampleWords(samples map[string]bool) []string {
+	words := make([]string, 0, len(samples))
+	for word := range samples {
+		words = append(words, word)
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if len([]rune(words[i])) != len([]rune(words[j])) {
+			return len([]rune(words[i])) < len([]rune(words[j]))
+		}
+		return words[i] < words[j]
+	})
+	return words
+}
@@ -0,0 +1,126 @@
+package learner
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Сокращение суффиксов после обучения: контрпримеры добавляют в таблицу все свои суффиксы,
+// и многие из них не различают ни одной пары строк. Оставляется набор, который различает
+// те же строки, что и все суффиксы, - таблица задаёт тот же автомат, но меньше по размеру
+
+// signatureCount - число различных строк представителей по столбцам columns
+func (et *EquivalenceTable) signatureCount(representatives []string, columns []int) int {
+	signatures := make(map[string]bool, len(representatives))
+	key := make([]byte, len(columns))
+	for _, prefix := range representatives {
+		row := et.Table[prefix]
+		for i, column := range columns {
+			key[i] = byte(row.Get(column))
+		}
+		signatures[string(key)] = true
+	}
+	return len(signatures)
+}
+
+// DistinguishingSuffixes - набор суффиксов, различающий те же строки таблицы, что и все
+// суффиксы. Набор собирается жадно (каждый раз берётся суффикс, дающий больше всего классов),
+// затем из него убираются лишние суффиксы, так что ни один оставшийся нельзя удалить.
+// ε остаётся всегда: по нему определяются заключительные состояния
+func (et *EquivalenceTable) DistinguishingSuffixes() []string {
+	classes := newRowClasses(et.Table)
+	var representatives []string
+	for _, prefix := range et.Prefixes.All() {
+		if classes.Add(prefix.Value) == prefix.Value {
+			representatives = append(representatives, prefix.Value)
+		}
+	}
+	target := len(representatives)
+
+	var chosen []int
+	if column, exists := et.Suffixes.Index(et.Epsilon); exists {
+		chosen = append(chosen, column)
+	}
+	used := make(map[int]bool)
+	for _, column := range chosen {
+		used[column] = true
+	}
+	for et.signatureCount(representatives, chosen) < target {
+		best, bestCount := -1, 0
+		for column := 0; column < et.Suffixes.Len(); column++ {
+			if used[column] {
+				continue
+			}
+			if count := et.signatureCount(representatives, append(chosen, column)); count > bestCount {
+				best, bestCount = column, count
+			}
+		}
+		if best < 0 {
+			break
+		}
+		chosen = append(chosen, best)
+		used[best] = true
+	}
+
+	// Убираем суффиксы, без которых строки всё равно различаются (ε не трогаем)
+	for i := len(chosen) - 1; i >= 0; i-- {
+		if et.Suffixes.All()[chosen[i]] == et.Epsilon {
+			continue
+		}
+		without := append(append([]int{}, chosen[:i]...), chosen[i+1:]...)
+		if et.signatureCount(representatives, without) == target {
+			chosen = without
+		}
+	}
+
+	sort.Ints(chosen)
+	suffixes := make([]string, len(chosen))
+	for i, column := range chosen {
+		suffixes[i] = et.Suffixes.All()[column]
+	}
+	return suffixes
+}
+
+// PruneSuffixes - удаление суффиксов, не нужных для различения строк; возвращает число удалённых
+// Словарь не меняется: ответы учителя на удалённые столбцы остаются в нём
+func (et *EquivalenceTable) PruneSuffixes() int {
+	kept := et.DistinguishingSuffixes()
+	removed := et.Suffixes.Len() - len(kept)
+	if removed == 0 {
+		return 0
+	}
+
+	table := make(map[string]*TableRow, len(et.Table))
+	for prefix, row := range et.Table {
+		pruned := &TableRow{}
+		for i, suffix := range kept {
+			column, _ := et.Suffixes.Index(suffix)
+			pruned.Set(i, row.Get(column))
+		}
+		table[prefix] = pruned
+	}
+	et.Table = table
+	et.Suffixes = NewSuffixSet(kept...)
+	return removed
+}
+
+// pruneSuffixes - сокращение суффиксов угаданной таблицы и, если recheck, повторная отправка
+// меньшей таблицы учителю. Число суффиксов до сокращения и удалённых - в Stats
+func pruneSuffixes(et *EquivalenceTable, recheck bool) error {
+	et.teacher.Stats.SuffixesBeforePruning = et.Suffixes.Len()
+	removed := et.PruneSuffixes()
+	et.teacher.Stats.PrunedSuffixes = removed
+	if !recheck || removed == 0 {
+		return nil
+	}
+
+	response, _ := et.AskForTable()
+	switch response {
+	case "true":
+		return nil
+	case "ERROR":
+		return fmt.Errorf("ошибка при проверке сокращённой таблицы учителем")
+	default:
+		return fmt.Errorf("учитель отверг сокращённую таблицу, контрпример: %s", et.display(response))
+	}
+}
@@ -0,0 +1,295 @@
+package learner
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// firstTokenRune - начало области частного использования Unicode: отсюда выдаются руны
+// многобуквенным символам
+const firstTokenRune = 0xE000
+
+// Symbols - символы алфавита учителя: руны и многобуквенные лексемы (if, then)
+// Внутри лернера каждый символ - одна руна: однобуквенный символ записывается самим собой,
+// лексема - своей руной из области частного использования. Поэтому слова остаются строками Go,
+// которые обходятся по рунам, а имена символов появляются только в запросах к учителю,
+// при выводе и в сохранённом автомате, где символы слова разделяются Separator
+type Symbols struct {
+	Separator string
+	names     []string        // Имена символов в порядке алфавита
+	runes     map[string]rune // Имя символа -> руна
+	byRune    map[rune]string // Руна -> имя символа
+}
+
+// NewSymbols - символы из букв alphabet и лексем tokens
+// Многобуквенным лексемам нужен непустой разделитель, который в них не встречается
+func NewSymbols(alphabet string, tokens []string, separator string) (*Symbols, error) {
+	symbols := &Symbols{
+		Separator: separator,
+		runes:     make(map[string]rune),
+		byRune:    make(map[rune]string),
+	}
+	add := func(name string, letter rune) error {
+		if _, exists := symbols.runes[name]; exists {
+			return nil
+		}
+		if other, used := symbols.byRune[letter]; used {
+			return fmt.Errorf("символы %q и %q записываются одной руной", other, name)
+		}
+		symbols.names = append(symbols.names, name)
+		symbols.runes[name] = letter
+		symbols.byRune[letter] = name
+		return nil
+	}
+
+	for _, letter := range alphabet {
+		if err := add(string(letter), letter); err != nil {
+			return nil, err
+		}
+	}
+	next := rune(firstTokenRune)
+	for _, token := range tokens {
+		var err error
+		switch {
+		case token == "":
+			return nil, fmt.Errorf("пустой символ в списке symbols")
+		case separator != "" && strings.Contains(token, separator):
+			return nil, fmt.Errorf("символ %q содержит разделитель %q", token, separator)
+		case utf8.RuneCountInString(token) == 1:
+			letter, _ := utf8.DecodeRuneInString(token)
+			err = add(token, letter)
+		case separator == "":
+			return nil, fmt.Errorf("для многобуквенного символа %q нужен разделитель symbol_separator", token)
+		default:
+			// Пропускаем руны, которые уже заняты буквами алфавита
+			for symbols.byRune[next] != "" {
+				next++
+			}
+			err = add(token, next)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return symbols, nil
+}
+
+// Plain - слова записываются без разделителя, и внутренняя запись совпадает с внешней
+// (многобуквенные символы без разделителя не допускаются)
+func (symbols *Symbols) Plain() bool {
+	return symbols == nil || symbols.Separator == ""
+}
+
+// SingleRune - все символы однобуквенные, и внутренняя руна символа - он сам
+func (symbols *Symbols) SingleRune() bool {
+	if symbols == nil {
+		return true
+	}
+	for _, name := range symbols.names {
+		if utf8.RuneCountInString(name) != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// Alphabet - внутренняя запись алфавита: по руне на символ
+func (symbols *Symbols) Alphabet() string {
+	var sb strings.Builder
+	for _, name := range symbols.names {
+		sb.WriteRune(symbols.runes[name])
+	}
+	return sb.String()
+}
+
+// Names - имена символов в порядке алфавита
+func (symbols *Symbols) Names() []string {
+	return symbols.names
+}
+
+// Name - имя символа по его руне; неизвестная руна записывается сама собой
+func (symbols *Symbols) Name(letter rune) string {
+	if symbols != nil {
+		if name, exists := symbols.byRune[letter]; exists {
+			return name
+		}
+	}
+	return string(letter)
+}
+
+// Encode - внешняя запись слова: имена символов через разделитель
+func (symbols *Symbols) Encode(word string) string {
+	if symbols.Plain() {
+		return word
+	}
+	parts := make([]string, 0, len(word))
+	for _, letter := range word {
+		parts = append(parts, symbols.Name(letter))
+	}
+	return strings.Join(parts, symbols.Separator)
+}
+
+// Decode - внутренняя запись слова из имён символов через разделитель
+func (symbols *Symbols) Decode(text string) (string, error) {
+	if symbols.Plain() || text == "" {
+		return text, nil
+	}
+	var sb strings.Builder
+	for _, name := range strings.Split(text, symbols.Separator) {
+		letter, exists := symbols.runes[name]
+		if !exists {
+			return "", fmt.Errorf("неизвестный символ %q в слове %q", name, text)
+		}
+		sb.WriteRune(letter)
+	}
+	return sb.String(), nil
+}
+
+// Translate - слово из внутренней записи символов from во внутреннюю запись symbols
+func (symbols *Symbols) Translate(word string, from *Symbols) (string, error) {
+	if from.SingleRune() && symbols.SingleRune() {
+		// Обе записи - сами буквы
+		return word, nil
+	}
+	var sb strings.Builder
+	for _, letter := range word {
+		name := from.Name(letter)
+		if symbols == nil {
+			sb.WriteString(name)
+			continue
+		}
+		target, exists := symbols.runes[name]
+		if !exists {
+			return "", fmt.Errorf("символ %q отсутствует в алфавите", name)
+		}
+		sb.WriteRune(target)
+	}
+	return sb.String(), nil
+}
+
+// SymbolTable - символы сохранённого автомата; nil, если символы однобуквенные
+func (dfa *DFA) SymbolTable() *Symbols {
+	if len(dfa.Symbols) == 0 {
+		return nil
+	}
+	symbols, err := NewSymbols("", dfa.Symbols, dfa.Separator)
+	if err != nil {
+		return nil
+	}
+	return symbols
+}
+
+// encodeDFA - копия автомата с именами символов вместо внутренних рун (для сохранения)
+func encodeDFA(dfa *DFA) *DFA {
+	symbols := dfa.SymbolTable()
+	if symbols == nil {
+		return dfa
+	}
+	encode := func(word string) string {
+		if word == dfa.Epsilon {
+			return word
+		}
+		return symbols.Encode(word)
+	}
+
+	result := *dfa
+	result.Alphabet = ""
+	result.States = make([]DFAState, len(dfa.States))
+	for i, state := range dfa.States {
+		result.States[i] = DFAState{
+			Access:      encode(state.Access),
+			Accepting:   state.Accepting,
+			Transitions: make(map[string]int, len(state.Transitions)),
+		}
+		for letter, target := range state.Transitions {
+			result.States[i].Transitions[encode(letter)] = target
+		}
+	}
+	if dfa.Words != nil {
+		result.Words = make(map[string]bool, len(dfa.Words))
+		for word, belongs := range dfa.Words {
+			result.Words[encode(word)] = belongs
+		}
+	}
+	return &result
+}
+
+// encodeNFA - копия недетерминированного автомата с именами символов вместо внутренних рун
+func encodeNFA(nfa *NFA) *NFA {
+	if len(nfa.Symbols) == 0 {
+		return nfa
+	}
+	symbols, err := NewSymbols("", nfa.Symbols, nfa.Separator)
+	if err != nil {
+		return nfa
+	}
+	encode := func(word string) string {
+		if word == nfa.Epsilon {
+			return word
+		}
+		return symbols.Encode(word)
+	}
+
+	result := *nfa
+	result.Alphabet = ""
+	result.States = make([]NFAState, len(nfa.States))
+	for i, state := range nfa.States {
+		result.States[i] = NFAState{
+			Access:      encode(state.Access),
+			Accepting:   state.Accepting,
+			Transitions: make(map[string][]int, len(state.Transitions)),
+		}
+		for letter, targets := range state.Transitions {
+			result.States[i].Transitions[encode(letter)] = targets
+		}
+	}
+	return &result
+}
+
+// decodeDFA - автомат с именами символов во внутренней записи (после загрузки)
+func decodeDFA(dfa *DFA) (*DFA, error) {
+	if len(dfa.Symbols) == 0 {
+		return dfa, nil
+	}
+	symbols, err := NewSymbols("", dfa.Symbols, dfa.Separator)
+	if err != nil {
+		return nil, err
+	}
+	decode := func(word string) (string, error) {
+		if word == dfa.Epsilon {
+			return word, nil
+		}
+		return symbols.Decode(word)
+	}
+
+	dfa.Alphabet = symbols.Alphabet()
+	for i := range dfa.States {
+		access, err := decode(dfa.States[i].Access)
+		if err != nil {
+			return nil, err
+		}
+		dfa.States[i].Access = access
+		transitions := make(map[string]int, len(dfa.States[i].Transitions))
+		for letter, target := range dfa.States[i].Transitions {
+			decoded, err := decode(letter)
+			if err != nil {
+				return nil, err
+			}
+			transitions[decoded] = target
+		}
+		dfa.States[i].Transitions = transitions
+	}
+	words := make(map[string]bool, len(dfa.Words))
+	for word, belongs := range dfa.Words {
+		decoded, err := decode(word)
+		if err != nil {
+			return nil, err
+		}
+		words[decoded] = belongs
+	}
+	if dfa.Words != nil {
+		dfa.Words = words
+	}
+	return dfa, nil
+}
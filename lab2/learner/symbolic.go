@@ -0,0 +1,98 @@
+package learner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SymbolicTransition - переход по предикату: множеству символов упорядоченного алфавита
+type SymbolicTransition struct {
+	Guard   string `json:"guard"`   // Запись предиката диапазонами, например 0-4,7
+	Letters string `json:"letters"` // Все символы предиката
+	Target  int    `json:"target"`
+}
+
+// SymbolicState - состояние символьного автомата
+type SymbolicState struct {
+	Access      string               `json:"access"`
+	Accepting   bool                 `json:"accepting"`
+	Transitions []SymbolicTransition `json:"transitions"`
+}
+
+// SymbolicDFA - автомат, переходы которого помечены предикатами вместо отдельных символов
+type SymbolicDFA struct {
+	Alphabet string          `json:"alphabet"`
+	Epsilon  string          `json:"epsilon"`
+	Start    int             `json:"start"`
+	States   []SymbolicState `json:"states"`
+}
+
+// orderedAlphabet - символы алфавита по возрастанию кода без повторов
+func orderedAlphabet(alphabet string) []rune {
+	letters := []rune(alphabet)
+	sort.Slice(letters, func(i, j int) bool { return letters[i] < letters[j] })
+	result := letters[:0]
+	for i, letter := range letters {
+		if i == 0 || letter != letters[i-1] {
+			result = append(result, letter)
+		}
+	}
+	return result
+}
+
+// FormatGuard - запись множества символов диапазонами подряд идущих кодов: 0-4,7,a-c
+func FormatGuard(letters []rune) string {
+	letters = orderedAlphabet(string(letters))
+	var parts []string
+	for i := 0; i < len(letters); {
+		j := i
+		for j+1 < len(letters) && letters[j+1] == letters[j]+1 {
+			j++
+		}
+		switch {
+		case j == i:
+			parts = append(parts, string(letters[i]))
+		case j == i+1:
+			parts = append(parts, string(letters[i]), string(letters[j]))
+		default:
+			parts = append(parts, fmt.Sprintf("%c-%c", letters[i], letters[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+// ToDFA - обычный ДКА: каждый предикат раскрывается в переходы по своим символам
+func (sdfa *SymbolicDFA) ToDFA() *DFA {
+	dfa := &DFA{Alphabet: sdfa.Alphabet, Epsilon: sdfa.Epsilon, Start: sdfa.Start}
+	for _, state := range sdfa.States {
+		dfaState := DFAState{Access: state.Access, Accepting: state.Accepting, Transitions: make(map[string]int)}
+		for _, transition := range state.Transitions {
+			for _, letter := range transition.Letters {
+				dfaState.Transitions[string(letter)] = transition.Target
+			}
+		}
+		dfa.States = append(dfa.States, dfaState)
+	}
+	return dfa
+}
+
+// String - текстовое описание автомата: по строке на переход
+func (sdfa *SymbolicDFA) String() string {
+	var sb strings.Builder
+	for i, state := range sdfa.States {
+		marker := ""
+		if i == sdfa.Start {
+			marker += " (начальное)"
+		}
+		if state.Accepting {
+			marker += " (заключительное)"
+		}
+		fmt.Fprintf(&sb, "q%d [%s]%s\n", i, state.Access, marker)
+		for _, transition := range state.Transitions {
+			fmt.Fprintf(&sb, "  %s -> q%d\n", transition.Guard, transition.Target)
+		}
+	}
+	return sb.String()
+}
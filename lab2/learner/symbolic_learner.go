@@ -0,0 +1,189 @@
+package learner
+
+import (
+	"fmt"
+	"sort"
+)
+
+// symbolicLearner - обучение символьного автомата
+// Для каждого состояния хранятся символы-свидетели: таблица спрашивает только о них, а остальные
+// символы упорядоченного алфавита идут туда же, куда ближайший слева свидетель.
+// Новый свидетель добавляется, только когда контрпример отделяет символ от его соседей
+type symbolicLearner struct {
+	et       *EquivalenceTable
+	letters  []rune   // Упорядоченный алфавит
+	access   []string // Представители состояний
+	suffixes []string
+	evidence [][]int // Номера символов-свидетелей каждого состояния по возрастанию
+}
+
+// LearnSymbolic - обучение символьного автомата для большого упорядоченного алфавита
+func LearnSymbolic(et *EquivalenceTable, alphabet string) (*SymbolicDFA, *DFA, error) {
+	learner := &symbolicLearner{
+		et:       et,
+		letters:  orderedAlphabet(alphabet),
+		access:   []string{""},
+		suffixes: []string{""},
+		evidence: [][]int{{0}},
+	}
+
+	for {
+		et.roundStart()
+		symbolic := learner.close()
+		hypothesis := symbolic.ToDFA()
+		counterexample, done, err := askForHypothesis(et, hypothesis)
+		if err != nil {
+			return nil, nil, err
+		}
+		if done {
+			return symbolic, hypothesis, nil
+		}
+		if err := learner.processCounterexample(hypothesis, counterexample); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// rows - строки таблицы для слов, вопросы задаются одним пакетом
+func (learner *symbolicLearner) rows(words []string) []string {
+	queries := make([]string, 0, len(words)*len(learner.suffixes))
+	for _, word := range words {
+		for _, suffix := range learner.suffixes {
+			queries = append(queries, word+suffix)
+		}
+	}
+	answers := learner.et.AskForWords(queries)
+
+	result := make([]string, len(words))
+	for i := range words {
+		row := make([]byte, len(learner.suffixes))
+		for k := range learner.suffixes {
+			if answers[i*len(learner.suffixes)+k] {
+				row[k] = '+'
+			} else {
+				row[k] = '-'
+			}
+		}
+		result[i] = string(row)
+	}
+	return result
+}
+
+// witness - символ-свидетель, которому подчиняется символ с номером position
+func (learner *symbolicLearner) witness(state, position int) int {
+	evidence := learner.evidence[state]
+	i := sort.SearchInts(evidence, position+1) - 1
+	if i < 0 {
+		i = 0
+	}
+	return evidence[i]
+}
+
+// close - приведение таблицы к полному виду и построение гипотезы
+// Строки представителей всегда различны, поэтому таблица непротиворечива
+func (learner *symbolicLearner) close() *SymbolicDFA {
+	for {
+		accessRows := learner.rows(learner.access)
+		index := make(map[string]int)
+		for i, row := range accessRows {
+			index[row] = i
+		}
+
+		var words []string
+		for state, word := range learner.access {
+			for _, position := range learner.evidence[state] {
+				words = append(words, word+string(learner.letters[position]))
+			}
+		}
+		extensionRows := learner.rows(words)
+
+		closed := true
+		for i, row := range extensionRows {
+			if _, exists := index[row]; !exists {
+				index[row] = len(learner.access)
+				learner.access = append(learner.access, words[i])
+				learner.evidence = append(learner.evidence, []int{0})
+				closed = false
+			}
+		}
+		if !closed {
+			continue
+		}
+
+		symbolic := &SymbolicDFA{Alphabet: string(learner.letters), Epsilon: learner.et.Epsilon, Start: 0}
+		k := 0
+		for state, word := range learner.access {
+			targets := make(map[int]int)
+			for _, position := range learner.evidence[state] {
+				targets[position] = index[extensionRows[k]]
+				k++
+			}
+
+			// Символы с одинаковой целью объединяются в один предикат
+			var order []int
+			guards := make(map[int][]rune)
+			for position, letter := range learner.letters {
+				target := targets[learner.witness(state, position)]
+				if _, exists := guards[target]; !exists {
+					order = append(order, target)
+				}
+				guards[target] = append(guards[target], letter)
+			}
+
+			symbolicState := SymbolicState{Access: learner.et.Word(word), Accepting: accessRows[state][0] == '+'}
+			for _, target := range order {
+				symbolicState.Transitions = append(symbolicState.Transitions, SymbolicTransition{
+					Guard:   FormatGuard(guards[target]),
+					Letters: string(guards[target]),
+					Target:  target,
+				})
+			}
+			symbolic.States = append(symbolic.States, symbolicState)
+		}
+		return symbolic
+	}
+}
+
+// processCounterexample - разбор контрпримера по Ривесту-Шапиру
+// В точке k, где ответ на access(q_k)·z[k:] меняется, переход из q_k по z[k] ошибочен:
+// символ становится свидетелем, а если переход по нему уже верен по таблице - добавляется суффикс
+func (learner *symbolicLearner) processCounterexample(hypothesis *DFA, counterexample string) error {
+	letters := []rune(counterexample)
+	_, path := hypothesis.Run(counterexample)
+	if len(path) != len(letters)+1 {
+		return fmt.Errorf("контрпример %s содержит символы вне алфавита", learner.et.Word(counterexample))
+	}
+
+	queries := make([]string, len(path))
+	for k, state := range path {
+		queries[k] = learner.access[state] + string(letters[k:])
+	}
+	answers := learner.et.AskForWords(queries)
+
+	for k := 0; k+1 < len(answers); k++ {
+		if answers[k] == answers[k+1] {
+			continue
+		}
+		state := path[k]
+		position := sort.Search(len(learner.letters), func(i int) bool { return learner.letters[i] >= letters[k] })
+		evidence := learner.evidence[state]
+		i := sort.SearchInts(evidence, position)
+		if i == len(evidence) || evidence[i] != position {
+			learner.evidence[state] = append(evidence[:i], append([]int{position}, evidence[i:]...)...)
+		}
+
+		// Если по таблице символ ведёт в то же состояние, что и в гипотезе, различие даёт только суффикс
+		rows := learner.rows([]string{learner.access[state] + string(letters[k]), learner.access[path[k+1]]})
+		suffix := string(letters[k+1:])
+		if rows[0] == rows[1] {
+			for _, existing := range learner.suffixes {
+				if existing == suffix {
+					return fmt.Errorf("суффикс %s из контрпримера уже есть в таблице", learner.et.Word(suffix))
+				}
+			}
+			learner.suffixes = append(learner.suffixes, suffix)
+		}
+		return nil
+	}
+	return fmt.Errorf("контрпример %s не расходится с гипотезой", learner.et.Word(counterexample))
+}
@@ -0,0 +1,254 @@
+package learner
+
+import (
+	"math/bits"
+)
+
+// Cell - значение ячейки таблицы: в таблице ДКА - принадлежность слова языку,
+// в таблице машины с выходами - номер выхода учителя (OutputTable.cellOf)
+type Cell uint32
+
+const (
+	CellUnknown Cell = iota // Учителю ещё не задан вопрос
+	CellAccept              // Слово принадлежит языку
+	CellReject              // Слово не принадлежит языку
+)
+
+// CellOf - значение ячейки по ответу учителя
+func CellOf(belonging bool) Cell {
+	if belonging {
+		return CellAccept
+	}
+	return CellReject
+}
+
+// String - запись ячейки при выводе таблицы: +, - или ?
+func (cell Cell) String() string {
+	switch cell {
+	case CellAccept:
+		return "+"
+	case CellReject:
+		return "-"
+	default:
+		return "?"
+	}
+}
+
+// Wire - запись ячейки для /checkTable: 1 или 0; неизвестная ячейка так не записывается
+func (cell Cell) Wire() string {
+	if cell == CellAccept {
+		return "1"
+	}
+	return "0"
+}
+
+// TableRow - строка таблицы в виде битовых множеств по номерам столбцов:
+// known - значение ячейки известно, planes[b] - бит b числа value-1 известной ячейки.
+// Строке таблицы ДКА хватает одного слоя (отвергается ли слово), строке таблицы машины
+// с выходами - по слою на каждый двоичный разряд номера выхода.
+// Хеш строки пересчитывается при каждом изменении ячейки, поэтому строки можно
+// раскладывать по классам через map без сравнения всех пар
+type TableRow struct {
+	known  []uint64
+	planes [][]uint64
+	hash   uint64
+}
+
+// cellHash - вклад ячейки в хеш строки (хеширование Зобриста: хеш - XOR вкладов)
+func cellHash(column int, value Cell) uint64 {
+	x := uint64(column)<<32 | uint64(value)
+	// splitmix64
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// Get - значение ячейки
+func (row *TableRow) Get(column int) Cell {
+	word, bit := column/64, uint64(1)<<(column%64)
+	if word >= len(row.known) || row.known[word]&bit == 0 {
+		return CellUnknown
+	}
+	value := Cell(1)
+	for b, plane := range row.planes {
+		if word < len(plane) && plane[word]&bit != 0 {
+			value += 1 << b
+		}
+	}
+	return value
+}
+
+// Set - запись значения ячейки с пересчётом хеша
+func (row *TableRow) Set(column int, value Cell) {
+	word, bit := column/64, uint64(1)<<(column%64)
+	for word >= len(row.known) {
+		row.known = append(row.known, 0)
+	}
+	if value != CellUnknown {
+		for len(row.planes) < bits.Len32(uint32(value-1)) {
+			row.planes = append(row.planes, nil)
+		}
+	}
+
+	if old := row.Get(column); old != CellUnknown {
+		row.hash ^= cellHash(column, old)
+	}
+	if value == CellUnknown {
+		row.known[word] &^= bit
+	} else {
+		row.known[word] |= bit
+	}
+	for b := range row.planes {
+		for word >= len(row.planes[b]) {
+			row.planes[b] = append(row.planes[b], 0)
+		}
+		if value != CellUnknown && (value-1)>>b&1 == 1 {
+			row.planes[b][word] |= bit
+		} else {
+			row.planes[b][word] &^= bit
+		}
+	}
+	if value != CellUnknown {
+		row.hash ^= cellHash(column, value)
+	}
+}
+
+// Hash - хеш строки
+func (row *TableRow) Hash() uint64 {
+	return row.hash
+}
+
+// Complete - известны ли значения всех columns ячеек
+func (row *TableRow) Complete(columns int) bool {
+	count := 0
+	for _, word := range row.known {
+		count += bits.OnesCount64(word)
+	}
+	return count == columns
+}
+
+// Equal - совпадение строк, включая неизвестные ячейки
+func (row *TableRow) Equal(other *TableRow) bool {
+	if row.hash != other.hash {
+		return false
+	}
+	if !equalBits(row.known, other.known) {
+		return false
+	}
+	planes := len(row.planes)
+	if len(other.planes) > planes {
+		planes = len(other.planes)
+	}
+	for b := 0; b < planes; b++ {
+		if !equalBits(planeAt(row.planes, b), planeAt(other.planes, b)) {
+			return false
+		}
+	}
+	return true
+}
+
+// planeAt - слой b строки; недостающий слой - пустое множество
+func planeAt(planes [][]uint64, b int) []uint64 {
+	if b < len(planes) {
+		return planes[b]
+	}
+	return nil
+}
+
+// equalBits - совпадение битовых множеств; недостающие слова считаются нулями
+func equalBits(a, b []uint64) bool {
+	length := len(a)
+	if len(b) > length {
+		length = len(b)
+	}
+	at := func(words []uint64, i int) uint64 {
+		if i < len(words) {
+			return words[i]
+		}
+		return 0
+	}
+	for i := 0; i < length; i++ {
+		if at(a, i) != at(b, i) {
+			return false
+		}
+	}
+	return true
+}
+
+// rowClasses - раскладка строк по классам равных строк через хеш
+type rowClasses struct {
+	buckets map[uint64][]string
+	rows    map[string]*TableRow
+}
+
+// newRowClasses - пустая раскладка для строк таблицы rows
+func newRowClasses(rows map[string]*TableRow) *rowClasses {
+	return &rowClasses{buckets: make(map[uint64][]string), rows: rows}
+}
+
+// Find - префикс раскладки с той же строкой, что и у prefix
+func (classes *rowClasses) Find(prefix string) (string, bool) {
+	row := classes.rows[prefix]
+	for _, candidate := range classes.buckets[row.Hash()] {
+		if classes.rows[candidate].Equal(row) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// Add - добавление префикса; возвращает представителя его класса (первый добавленный)
+func (classes *rowClasses) Add(prefix string) string {
+	if representative, exists := classes.Find(prefix); exists {
+		return representative
+	}
+	hash := classes.rows[prefix].Hash()
+	classes.buckets[hash] = append(classes.buckets[hash], prefix)
+	return prefix
+}
+
+// closeRows - общий для таблиц шаг приведения к полному виду: неглавный префикс, строки
+// которого нет среди главных, передаётся promote и сразу участвует в следующих сравнениях,
+// поэтому из префиксов с одной и той же новой строкой главным становится только первый.
+// Для остальных неглавных префиксов вызывается same с представителем их класса
+func closeRows(prefixes *PrefixSet, rows map[string]*TableRow, promote func(prefix string), same func(prefix, representative string)) {
+	// Классы строк главной части ищутся по хешу, а не перебором всех главных префиксов
+	classes := newRowClasses(rows)
+	for _, prefix := range prefixes.All() {
+		if prefix.IsMain {
+			classes.Add(prefix.Value)
+		}
+	}
+	for _, prefix := range prefixes.All() {
+		if prefix.IsMain {
+			continue
+		}
+		if representative, exists := classes.Find(prefix.Value); exists {
+			if same != nil {
+				same(prefix.Value, representative)
+			}
+			continue
+		}
+		promote(prefix.Value)
+		classes.Add(prefix.Value)
+	}
+}
+
+// findEqualMainRows - общий для таблиц обход при проверке непротиворечивости: каждый главный
+// префикс, строка которого совпала с уже встреченной, передаётся fn вместе с представителем
+// класса (достаточно сравнить продолжения с ним, а не со всеми префиксами класса).
+// Обход прекращается, когда fn возвращает true - противоречие найдено
+func findEqualMainRows(prefixes *PrefixSet, rows map[string]*TableRow, fn func(representative, prefix string) bool) bool {
+	classes := newRowClasses(rows)
+	for _, prefix := range prefixes.All() {
+		if !prefix.IsMain {
+			continue
+		}
+		representative := classes.Add(prefix.Value)
+		if representative != prefix.Value && fn(representative, prefix.Value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package learner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Stats - счётчики обучения
+type Stats struct {
+	MembershipQueries  int `json:"membership_queries"`  // Слов, отправленных учителю
+	EquivalenceQueries int `json:"equivalence_queries"` // Гипотез, отправленных учителю
+	TrueWords          int `json:"true_words"`          // Слов словаря, принадлежащих языку
+	// Вопросов о продолжениях, не заданных благодаря повышению одного префикса на класс строк
+	SavedExtensionQueries int `json:"saved_extension_queries"`
+	// prune_suffixes: суффиксов в угаданной таблице и сколько из них удалено
+	SuffixesBeforePruning int `json:"suffixes_before_pruning"`
+	PrunedSuffixes        int `json:"pruned_suffixes"`
+	// seed_file: загружено слов, префиксов и суффиксов
+	SeedWords    int `json:"seed_words"`
+	SeedPrefixes int `json:"seed_prefixes"`
+	SeedSuffixes int `json:"seed_suffixes"`
+	// previous_hypothesis: перенесено префиксов и суффиксов, перепроверено слов
+	// и слова (во внешней записи), ответ на которые изменился
+	PreviousPrefixes int      `json:"previous_prefixes"`
+	PreviousSuffixes int      `json:"previous_suffixes"`
+	RevalidatedWords int      `json:"revalidated_words"`
+	ChangedWords     []string `json:"changed_words,omitempty"`
+}
+
+// Teacher - учитель: MAT-сервер или пользователь в ручном режиме
+// Таблицы, созданные для одного обучения, ссылаются на одного учителя и делят его счётчики
+type Teacher struct {
+	Mode        string // manual - ответы вводит пользователь, иначе - MAT-сервер
+	Server      string
+	Port        string
+	WireEpsilon string   // Запись пустого слова в запросах к MAT-серверу
+	Symbols     *Symbols // Имена символов в словах для учителя; nil - по руне на символ
+	Stats       Stats
+	Hooks       *Hooks // Подписчики на события обучения; nil - без подписчиков
+	ctx         context.Context
+	round       int // Последний раунд, о начале которого сообщено подписчикам
+}
+
+// NewTeacher - учитель по конфигурации
+func NewTeacher(config *Config) *Teacher {
+	return &Teacher{
+		Mode:        config.LearnerMode,
+		Server:      config.ServerAddr,
+		Port:        config.ServerPort,
+		WireEpsilon: orEpsilon(config.WireEpsilon, defaultEpsilon),
+		ctx:         context.Background(),
+	}
+}
+
+// Err - ошибка контекста обучения, если оно отменено
+func (teacher *Teacher) Err() error {
+	return teacher.ctx.Err()
+}
+
+// url - адрес метода MAT-сервера
+func (teacher *Teacher) url(method string) string {
+	return fmt.Sprintf("http://%s:%s/%s", teacher.Server, teacher.Port, method)
+}
+
+// toWire - слово с пустым словом epsilon в записи для MAT-сервера
+func (teacher *Teacher) toWire(word, epsilon string) string {
+	return orEpsilon(teacher.Symbols.Encode(trimEpsilon(word, epsilon)), teacher.WireEpsilon)
+}
+
+// fromWire - слово MAT-сервера в записи с пустым словом epsilon
+func (teacher *Teacher) fromWire(word, epsilon string) (string, error) {
+	decoded, err := teacher.Symbols.Decode(trimEpsilon(word, teacher.WireEpsilon))
+	if err != nil {
+		return "", err
+	}
+	return orEpsilon(decoded, epsilon), nil
+}
+
+// SetMode - выбор одного из режимов MAT: easy, medium, hard
+// Возвращает наибольший размер лексемы и глубину вложенности скобок
+func (teacher *Teacher) SetMode(mode string) (int, int, error) {
+	requestBody, err := json.Marshal(map[string]string{
+		"mode": mode,
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("ошибка при формировании тела запроса: %v", err)
+	}
+
+	resp, err := http.Post(teacher.url("generate"), "application/json", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return 0, 0, fmt.Errorf("ошибка при отправке запроса: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		MaxLexemeSize     int `json:"maxLexemeSize"`
+		MaxBracketNesting int `json:"maxBracketNesting"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return 0, 0, fmt.Errorf("ошибка при декодировании JSON: %v", err)
+	}
+	return response.MaxLexemeSize, response.MaxBracketNesting, nil
+}
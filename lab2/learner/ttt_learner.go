@@ -0,0 +1,320 @@
+package learner
+
+import (
+	"fmt"
+)
+
+// tttLearner - состояние алгоритма TTT
+// Гипотеза задаётся остовным деревом строк доступа: строка доступа нового состояния - строка
+// доступа родителя плюс буква. Остальные переходы указывают на вершины дерева различения
+// и досеиваются до листьев по мере его роста
+type tttLearner struct {
+	et          *EquivalenceTable
+	tree        *DiscriminationTree
+	alphabet    string
+	letters     []string
+	transitions [][]*DTNode // Переходы: состояние, номер буквы -> вершина дерева различения
+}
+
+// LearnTTT - обучение алгоритмом TTT
+// Контрпример разбирается двоичным поиском (Ривест-Шапир), новое состояние отделяется
+// временным суффиксом - хвостом контрпримера, а затем временные суффиксы по возможности
+// заменяются окончательными вида a·v, где v - уже окончательный суффикс
+func LearnTTT(et *EquivalenceTable, alphabet string) (*DFA, error) {
+	learner := &tttLearner{
+		et:       et,
+		tree:     NewDiscriminationTree(et),
+		alphabet: alphabet,
+	}
+	for _, letter := range alphabet {
+		learner.letters = append(learner.letters, string(letter))
+	}
+	learner.addState()
+
+	for {
+		et.roundStart()
+		hypothesis := learner.hypothesis()
+		counterexample, done, err := askForHypothesis(et, hypothesis)
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return hypothesis, nil
+		}
+
+		// Контрпример разбирается, пока гипотеза ошибается на нём
+		belonging := et.Words.Belongs(et.Word(counterexample))
+		for hypothesis.Accepts(counterexample) != belonging {
+			if err := learner.processCounterexample(hypothesis, counterexample); err != nil {
+				return nil, err
+			}
+			learner.finalize()
+			hypothesis = learner.hypothesis()
+		}
+	}
+}
+
+// addState - переходы для нового состояния пока просеиваются от корня
+func (learner *tttLearner) addState() {
+	row := make([]*DTNode, len(learner.letters))
+	for i := range row {
+		row[i] = learner.tree.Root
+	}
+	learner.transitions = append(learner.transitions, row)
+}
+
+// siftTransitions - досеивание всех переходов до листьев
+func (learner *tttLearner) siftTransitions() {
+	var starts []*DTNode
+	var words []string
+	for state, row := range learner.transitions {
+		for i, node := range row {
+			if !node.IsLeaf() {
+				starts = append(starts, node)
+				words = append(words, learner.tree.Leaves[state].Access+learner.letters[i])
+			}
+		}
+	}
+	leaves := learner.tree.SiftFrom(starts, words)
+	k := 0
+	for _, row := range learner.transitions {
+		for i, node := range row {
+			if !node.IsLeaf() {
+				row[i] = leaves[k]
+				k++
+			}
+		}
+	}
+}
+
+// hypothesis - текущая гипотеза
+func (learner *tttLearner) hypothesis() *DFA {
+	learner.siftTransitions()
+	accesses := make([]string, len(learner.tree.Leaves))
+	for i, leaf := range learner.tree.Leaves {
+		accesses[i] = leaf.Access
+	}
+	accepting := learner.et.AskForWords(accesses)
+
+	dfa := &DFA{Alphabet: learner.alphabet, Epsilon: learner.et.Epsilon, Start: 0}
+	for state, row := range learner.transitions {
+		dfaState := DFAState{
+			Access:      learner.et.Word(accesses[state]),
+			Accepting:   accepting[state],
+			Transitions: make(map[string]int),
+		}
+		for i, node := range row {
+			dfaState.Transitions[learner.letters[i]] = learner.tree.index[node]
+		}
+		dfa.States = append(dfa.States, dfaState)
+	}
+	return dfa
+}
+
+// processCounterexample - разбор контрпримера двоичным поиском
+// Ищется i, для которого ⌊c[:i]⌋·c[i:] и ⌊c[:i+1]⌋·c[i+1:] расходятся в ответе учителя;
+// тогда ⌊c[:i]⌋·c[i] становится новым состоянием, а c[i+1:] - временным суффиксом
+func (learner *tttLearner) processCounterexample(hypothesis *DFA, counterexample string) error {
+	letters := []rune(counterexample)
+	_, path := hypothesis.Run(counterexample)
+	if len(path) != len(letters)+1 {
+		return fmt.Errorf("гипотеза не определена на контрпримере %s", learner.et.Word(counterexample))
+	}
+	alpha := func(i int) bool {
+		access := learner.tree.Leaves[path[i]].Access
+		return learner.et.AskForWords([]string{access + string(letters[i:])})[0]
+	}
+
+	low, high := 0, len(letters)
+	lowValue := alpha(low)
+	if lowValue == alpha(high) {
+		return fmt.Errorf("контрпример %s не расходится с гипотезой", learner.et.Word(counterexample))
+	}
+	for high-low > 1 {
+		middle := (low + high) / 2
+		if alpha(middle) == lowValue {
+			low = middle
+		} else {
+			high = middle
+		}
+	}
+
+	source := path[low]
+	letterIndex := -1
+	for i, letter := range learner.letters {
+		if letter == string(letters[low]) {
+			letterIndex = i
+		}
+	}
+	if letterIndex < 0 {
+		return fmt.Errorf("буква %c контрпримера не входит в алфавит", letters[low])
+	}
+
+	access := learner.tree.Leaves[source].Access + string(letters[low])
+	newLeaf, err := learner.tree.Split(learner.tree.Leaves[path[low+1]], string(letters[low+1:]), access)
+	if err != nil {
+		return err
+	}
+	newLeaf.Parent.Temporary = true
+
+	// Переход становится переходом остовного дерева
+	learner.addState()
+	learner.transitions[source][letterIndex] = newLeaf
+	return nil
+}
+
+// finalize - замена временных суффиксов в корнях блоков на окончательные
+// Блок - поддерево из временных вершин. Его корень заменяется окончательным суффиксом
+// (ε или a·v, где v уже окончательный), если тот делит состояния блока; поддеревья блока
+// при этом разбиваются по новому суффиксу
+func (learner *tttLearner) finalize() {
+	for changed := true; changed; {
+		changed = false
+		learner.siftTransitions()
+		for _, root := range learner.blockRoots() {
+			if learner.finalizeBlock(root) {
+				changed = true
+				break
+			}
+		}
+	}
+}
+
+// blockRoots - временные вершины с окончательным родителем
+func (learner *tttLearner) blockRoots() []*DTNode {
+	var result []*DTNode
+	var walk func(node *DTNode)
+	walk = func(node *DTNode) {
+		if node.IsLeaf() {
+			return
+		}
+		if node.Temporary && (node.Parent == nil || !node.Parent.Temporary) {
+			result = append(result, node)
+		}
+		walk(node.Children[0])
+		walk(node.Children[1])
+	}
+	walk(learner.tree.Root)
+	return result
+}
+
+// finalCandidates - окончательные суффиксы, которые могут разделить состояния блока
+// ε, если состояния блока различаются заключительностью, и a·v, если переходы по a
+// из пары состояний блока разделяет окончательная вершина с суффиксом v
+func (learner *tttLearner) finalCandidates(states []int) []string {
+	var candidates []string
+	seen := make(map[string]bool)
+	add := func(candidate string) {
+		if !seen[candidate] {
+			seen[candidate] = true
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	accesses := make([]string, len(states))
+	for k, state := range states {
+		accesses[k] = learner.tree.Leaves[state].Access
+	}
+	accepting := learner.et.AskForWords(accesses)
+	for k := range accepting {
+		if accepting[k] != accepting[0] {
+			add("")
+			break
+		}
+	}
+
+	for i, letter := range learner.letters {
+		for _, other := range states[1:] {
+			first := learner.transitions[states[0]][i]
+			second := learner.transitions[other][i]
+			if first == second {
+				continue
+			}
+			if lca := learner.tree.LCA(first, second); !lca.Temporary {
+				add(letter + lca.Discriminator)
+			}
+		}
+	}
+	return candidates
+}
+
+// finalizeBlock - замена корня блока окончательным суффиксом, если он нашёлся
+func (learner *tttLearner) finalizeBlock(root *DTNode) bool {
+	states := learner.tree.LeavesBelow(root)
+	for _, discriminator := range learner.finalCandidates(states) {
+		words := make([]string, len(states))
+		for k, state := range states {
+			words[k] = learner.tree.Leaves[state].Access + discriminator
+		}
+		answers := learner.et.AskForWords(words)
+
+		sides := [2]map[int]bool{{}, {}}
+		for k, state := range states {
+			if answers[k] {
+				sides[1][state] = true
+			} else {
+				sides[0][state] = true
+			}
+		}
+		if len(sides[0]) == 0 || len(sides[1]) == 0 {
+			continue
+		}
+
+		// Переходы, просеянные внутрь блока по старым суффиксам, запоминаются до перестройки:
+		// extract переносит листья блока под новые вершины, и IsBelow(root) для них уже ложно
+		type transition struct{ state, letter int }
+		var stale []transition
+		for state, row := range learner.transitions {
+			for k, node := range row {
+				if node == root || node.IsBelow(root) {
+					stale = append(stale, transition{state, k})
+				}
+			}
+		}
+
+		// Новая окончательная вершина, под ней - части блока для каждой стороны
+		replacement := &DTNode{Discriminator: discriminator, Parent: root.Parent}
+		for side := range sides {
+			replacement.Children[side] = learner.extract(root, sides[side])
+			replacement.Children[side].Parent = replacement
+		}
+		if root.Parent == nil {
+			learner.tree.Root = replacement
+		} else if root.Parent.Children[0] == root {
+			root.Parent.Children[0] = replacement
+		} else {
+			root.Parent.Children[1] = replacement
+		}
+
+		// Эти слова просеиваются заново, начиная с новой окончательной вершины
+		for _, entry := range stale {
+			learner.transitions[entry.state][entry.letter] = replacement
+		}
+		return true
+	}
+	return false
+}
+
+// extract - копия поддерева, в которой оставлены только листья состояний из keep
+// Вершины, у которых остался один потомок, схлопываются
+func (learner *tttLearner) extract(node *DTNode, keep map[int]bool) *DTNode {
+	if node.IsLeaf() {
+		if keep[learner.tree.index[node]] {
+			return node
+		}
+		return nil
+	}
+	left := learner.extract(node.Children[0], keep)
+	right := learner.extract(node.Children[1], keep)
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+	copied := &DTNode{Discriminator: node.Discriminator, Temporary: node.Temporary}
+	copied.Children = [2]*DTNode{left, right}
+	left.Parent = copied
+	right.Parent = copied
+	return copied
+}
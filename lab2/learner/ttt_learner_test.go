@@ -0,0 +1,17 @@
+package learner
+
+import (
+	"testing"
+)
+
+func TestLearnTTTMinimal(t *testing.T) {
+	for name, target := range learnerTargets() {
+		t.Run(name, func(t *testing.T) {
+			hypothesis, err := LearnTTT(newTestTable(t, target), target.Alphabet)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkMinimalHypothesis(t, target, hypothesis)
+		})
+	}
+}
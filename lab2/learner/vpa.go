@@ -0,0 +1,147 @@
+package learner
+
+import (
+	"fmt"
+	"strings"
+)
+
+// VPAState - состояние автомата с магазинной памятью, управляемой входом
+type VPAState struct {
+	Access    string         `json:"access"`    // Сбалансированное слово-представитель
+	Accepting bool           `json:"accepting"` // Заключительное (при пустом стеке)
+	Internal  map[string]int `json:"internal"`  // Переходы по внутренним символам
+	// Переходы по закрывающим символам: ключ "p c r" - состояние и символ из стека, закрывающий символ
+	Returns map[string]int `json:"returns"`
+}
+
+// VPA - автомат с магазинной памятью, управляемой входом (visibly pushdown automaton)
+// Открывающий символ c в состоянии q кладёт в стек пару (q, c) и переводит в начальное состояние,
+// закрывающий r в состоянии q снимает пару (p, c) и переводит в Returns["p c r"] состояния q.
+// Слово принимается, если стек пуст и состояние заключительное
+type VPA struct {
+	Calls     string     `json:"calls"`
+	Returns   string     `json:"returns"`
+	Internals string     `json:"internals"`
+	Epsilon   string     `json:"epsilon"`
+	Start     int        `json:"start"`
+	States    []VPAState `json:"states"`
+}
+
+// vpaStackItem - элемент стека: состояние до открывающего символа и сам символ
+type vpaStackItem struct {
+	State int
+	Call  string
+}
+
+// returnKey - ключ перехода по закрывающему символу
+func returnKey(stackState int, call, ret string) string {
+	return fmt.Sprintf("%d %s %s", stackState, call, ret)
+}
+
+// vpaConfiguration - текущее состояние и стек
+type vpaConfiguration struct {
+	State int
+	Stack []vpaStackItem
+}
+
+// key - ключ конфигурации
+func (config vpaConfiguration) key() string {
+	return fmt.Sprint(config.State, config.Stack)
+}
+
+// Step - переход конфигурации по символу; false, если закрывающему символу нечего снять
+func (vpa *VPA) Step(config vpaConfiguration, letter string) (vpaConfiguration, bool) {
+	switch {
+	case strings.Contains(vpa.Calls, letter):
+		stack := append(append([]vpaStackItem{}, config.Stack...), vpaStackItem{config.State, letter})
+		return vpaConfiguration{State: vpa.Start, Stack: stack}, true
+	case strings.Contains(vpa.Returns, letter):
+		if len(config.Stack) == 0 {
+			return config, false
+		}
+		top := config.Stack[len(config.Stack)-1]
+		target, ok := vpa.States[config.State].Returns[returnKey(top.State, top.Call, letter)]
+		if !ok {
+			return config, false
+		}
+		return vpaConfiguration{State: target, Stack: config.Stack[:len(config.Stack)-1]}, true
+	default:
+		target, ok := vpa.States[config.State].Internal[letter]
+		if !ok {
+			return config, false
+		}
+		return vpaConfiguration{State: target, Stack: config.Stack}, true
+	}
+}
+
+// Accepts - принадлежит ли слово языку автомата
+func (vpa *VPA) Accepts(word string) bool {
+	config := vpaConfiguration{State: vpa.Start}
+	for _, letter := range word {
+		next, ok := vpa.Step(config, string(letter))
+		if !ok {
+			return false
+		}
+		config = next
+	}
+	return len(config.Stack) == 0 && vpa.States[config.State].Accepting
+}
+
+// Flatten - ДКА, совпадающий с автоматом на словах глубины вложенности не больше maxDepth
+// Состояния ДКА - достижимые конфигурации; переполнение стека и лишний закрывающий
+// символ ведут в тупиковое состояние
+func (vpa *VPA) Flatten(maxDepth int) *DFA {
+	alphabet := vpa.Internals + vpa.Calls + vpa.Returns
+	dfa := &DFA{Alphabet: alphabet, Epsilon: vpa.Epsilon, Start: 0}
+
+	start := vpaConfiguration{State: vpa.Start}
+	configs := []vpaConfiguration{start}
+	access := []string{""}
+	index := map[string]int{start.key(): 0}
+	sinkUsed := false
+
+	for i := 0; i < len(configs); i++ {
+		config := configs[i]
+		state := DFAState{
+			Access:      orEpsilon(access[i], vpa.Epsilon),
+			Accepting:   len(config.Stack) == 0 && vpa.States[config.State].Accepting,
+			Transitions: make(map[string]int),
+		}
+		for _, letter := range alphabet {
+			next, ok := vpa.Step(config, string(letter))
+			if !ok || len(next.Stack) > maxDepth {
+				// Номер тупикового состояния пока неизвестен
+				state.Transitions[string(letter)] = -1
+				sinkUsed = true
+				continue
+			}
+			target, exists := index[next.key()]
+			if !exists {
+				target = len(configs)
+				index[next.key()] = target
+				configs = append(configs, next)
+				access = append(access, access[i]+string(letter))
+			}
+			state.Transitions[string(letter)] = target
+		}
+		dfa.States = append(dfa.States, state)
+	}
+
+	// Тупиковое состояние добавляется последним
+	if sinkUsed {
+		sink := len(dfa.States)
+		sinkState := DFAState{Access: "", Transitions: make(map[string]int)}
+		for _, letter := range alphabet {
+			sinkState.Transitions[string(letter)] = sink
+		}
+		dfa.States = append(dfa.States, sinkState)
+		for i := range dfa.States {
+			for letter, target := range dfa.States[i].Transitions {
+				if target < 0 {
+					dfa.States[i].Transitions[letter] = sink
+				}
+			}
+		}
+	}
+	return dfa
+}
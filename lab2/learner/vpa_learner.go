@@ -0,0 +1,215 @@
+package learner
+
+import (
+	"fmt"
+	"strings"
+)
+
+// vpaLearner - обучение автомата с магазинной памятью, управляемой входом
+// Строки таблицы - сбалансированные слова, столбцы - контексты (u, v): в ячейке ответ на u·w·v.
+// Продолжения строк: w·i для внутренних символов и p·c·q·r для пар строк p, q главной части
+type vpaLearner struct {
+	et        *EquivalenceTable
+	calls     string
+	returns   string
+	internals string
+	maxDepth  int
+	access    []string // Главная часть: представители состояний
+	contexts  []Pair   // Контексты: First - левая часть, Second - правая
+}
+
+// vpaExtension - продолжение главной части; для закрывающих символов хранятся p, c, q и r
+type vpaExtension struct {
+	Word   string
+	From   int    // Состояние, из которого ведёт переход (q для закрывающего символа)
+	Letter string // Внутренний или закрывающий символ
+	Stack  int    // Состояние p, снятое со стека
+	Call   string // Открывающий символ c, снятый со стека
+}
+
+// LearnVPA - обучение автомата с магазинной памятью по разбиению алфавита на
+// открывающие, закрывающие и внутренние символы. MAT получает минимальный ДКА, совпадающий с
+// гипотезой на словах глубины не больше maxDepth: развёртка конфигураций без минимизации
+// растёт с глубиной экспоненциально
+func LearnVPA(et *EquivalenceTable, alphabet, calls, returns string, maxDepth int) (*VPA, *DFA, error) {
+	internals, _ := RemoveChars(calls+returns, alphabet)
+	learner := &vpaLearner{
+		et:        et,
+		calls:     calls,
+		returns:   returns,
+		internals: internals,
+		maxDepth:  maxDepth,
+		access:    []string{""},
+		contexts:  []Pair{{First: "", Second: ""}},
+	}
+
+	for {
+		et.roundStart()
+		vpa := learner.close()
+		hypothesis := vpa.Flatten(maxDepth).Minimize()
+		counterexample, done, err := askForHypothesis(et, hypothesis)
+		if err != nil {
+			return nil, nil, err
+		}
+		if done {
+			return vpa, hypothesis, nil
+		}
+		if err := learner.processCounterexample(vpa, counterexample); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// extensions - продолжения главной части таблицы
+func (learner *vpaLearner) extensions() []vpaExtension {
+	var result []vpaExtension
+	for from, word := range learner.access {
+		for _, letter := range learner.internals {
+			result = append(result, vpaExtension{Word: word + string(letter), From: from, Letter: string(letter)})
+		}
+	}
+	for stack, outer := range learner.access {
+		for _, call := range learner.calls {
+			for from, inner := range learner.access {
+				for _, ret := range learner.returns {
+					result = append(result, vpaExtension{
+						Word:   outer + string(call) + inner + string(ret),
+						From:   from,
+						Letter: string(ret),
+						Stack:  stack,
+						Call:   string(call),
+					})
+				}
+			}
+		}
+	}
+	return result
+}
+
+// rows - строки таблицы для слов, вопросы задаются одним пакетом
+func (learner *vpaLearner) rows(words []string) []string {
+	queries := make([]string, 0, len(words)*len(learner.contexts))
+	for _, word := range words {
+		for _, context := range learner.contexts {
+			queries = append(queries, context.First+word+context.Second)
+		}
+	}
+	answers := learner.et.AskForWords(queries)
+
+	result := make([]string, len(words))
+	for i := range words {
+		row := make([]byte, len(learner.contexts))
+		for k := range learner.contexts {
+			if answers[i*len(learner.contexts)+k] {
+				row[k] = '+'
+			} else {
+				row[k] = '-'
+			}
+		}
+		result[i] = string(row)
+	}
+	return result
+}
+
+// close - приведение таблицы к полному виду и построение гипотезы
+// Строки главной части всегда различны, поэтому таблица непротиворечива
+func (learner *vpaLearner) close() *VPA {
+	for {
+		accessRows := learner.rows(learner.access)
+		index := make(map[string]int)
+		for i, row := range accessRows {
+			index[row] = i
+		}
+
+		extensions := learner.extensions()
+		words := make([]string, len(extensions))
+		for i, extension := range extensions {
+			words[i] = extension.Word
+		}
+		extensionRows := learner.rows(words)
+
+		closed := true
+		for i, row := range extensionRows {
+			if _, exists := index[row]; !exists {
+				index[row] = len(learner.access)
+				learner.access = append(learner.access, extensions[i].Word)
+				closed = false
+			}
+		}
+		if !closed {
+			continue
+		}
+
+		vpa := &VPA{Calls: learner.calls, Returns: learner.returns, Internals: learner.internals, Epsilon: learner.et.Epsilon, Start: 0}
+		for i, word := range learner.access {
+			vpa.States = append(vpa.States, VPAState{
+				Access:    learner.et.Word(word),
+				Accepting: accessRows[i][0] == '+',
+				Internal:  make(map[string]int),
+				Returns:   make(map[string]int),
+			})
+		}
+		for i, extension := range extensions {
+			target := index[extensionRows[i]]
+			if extension.Call == "" {
+				vpa.States[extension.From].Internal[extension.Letter] = target
+			} else {
+				vpa.States[extension.From].Returns[returnKey(extension.Stack, extension.Call, extension.Letter)] = target
+			}
+		}
+		return vpa
+	}
+}
+
+// processCounterexample - разбор контрпримера
+// Для каждого k префикс контрпримера заменяется представителями: состояния в стеке и текущего
+// состояния гипотезы. Там, где ответ учителя меняется между k и k+1, переход гипотезы ошибается,
+// и его исправляет контекст (левая часть стека после шага, остаток контрпримера)
+func (learner *vpaLearner) processCounterexample(vpa *VPA, counterexample string) error {
+	belonging := learner.et.Words.Belongs(learner.et.Word(counterexample))
+	if vpa.Accepts(counterexample) == belonging {
+		return fmt.Errorf("контрпример %s глубже границы вложенности %d", learner.et.Word(counterexample), learner.maxDepth)
+	}
+
+	letters := []rune(counterexample)
+	configs := []vpaConfiguration{{State: vpa.Start}}
+	for _, letter := range letters {
+		next, ok := vpa.Step(configs[len(configs)-1], string(letter))
+		if !ok {
+			return fmt.Errorf("контрпример %s не сбалансирован: такие слова автомат не распознаёт", learner.et.Word(counterexample))
+		}
+		configs = append(configs, next)
+	}
+	if len(configs[len(configs)-1].Stack) > 0 {
+		return fmt.Errorf("контрпример %s не сбалансирован: такие слова автомат не распознаёт", learner.et.Word(counterexample))
+	}
+
+	stackPart := func(config vpaConfiguration) string {
+		var sb strings.Builder
+		for _, item := range config.Stack {
+			sb.WriteString(learner.access[item.State])
+			sb.WriteString(item.Call)
+		}
+		return sb.String()
+	}
+	queries := make([]string, len(configs))
+	for k, config := range configs {
+		queries[k] = stackPart(config) + learner.access[config.State] + string(letters[k:])
+	}
+	answers := learner.et.AskForWords(queries)
+
+	for k := 0; k+1 < len(answers); k++ {
+		if answers[k] == answers[k+1] {
+			continue
+		}
+		context := Pair{First: stackPart(configs[k+1]), Second: string(letters[k+1:])}
+		for _, existing := range learner.contexts {
+			if existing == context {
+				return fmt.Errorf("контекст (%s, %s) из контрпримера уже есть в таблице", learner.et.Word(context.First), learner.et.Word(context.Second))
+			}
+		}
+		learner.contexts = append(learner.contexts, context)
+		return nil
+	}
+	return fmt.Errorf("контрпример %s не расходится с гипотезой", learner.et.Word(counterexample))
+}
@@ -0,0 +1,47 @@
+package learner
+
+import (
+	"testing"
+)
+
+// evenBracketed - ДКА правильных скобочных слов над {(, ), a} глубины не больше depth
+// с чётным числом букв a; состояние - глубина и чётность, последнее - тупиковое
+func evenBracketed(depth int) *DFA {
+	dfa := &DFA{Alphabet: "()a", Epsilon: "ε"}
+	sink := 2 * (depth + 1)
+	for level := 0; level <= depth; level++ {
+		for parity := 0; parity < 2; parity++ {
+			opening, closing := sink, sink
+			if level < depth {
+				opening = 2*(level+1) + parity
+			}
+			if level > 0 {
+				closing = 2*(level-1) + parity
+			}
+			dfa.States = append(dfa.States, DFAState{
+				Access:      "ε",
+				Accepting:   level == 0 && parity == 0,
+				Transitions: map[string]int{"(": opening, ")": closing, "a": 2*level + 1 - parity},
+			})
+		}
+	}
+	dfa.States = append(dfa.States, DFAState{Access: "ε", Transitions: map[string]int{"(": sink, ")": sink, "a": sink}})
+	return dfa
+}
+
+func TestLearnVPAHypothesisIsMinimal(t *testing.T) {
+	// Развёртка конфигураций хранит в стеке чётность под каждой скобкой: без минимизации
+	// состояний в ней 2^(depth+2)-1 вместо 2·(depth+1)+1
+	const depth = 4
+	target := evenBracketed(depth)
+	vpa, hypothesis, err := LearnVPA(newTestTable(t, target), "()a", "(", ")", depth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkMinimalHypothesis(t, target, hypothesis)
+	for _, word := range []string{"", "aa", "(a)a", "((a)(a))", "(((())))"} {
+		if vpa.Accepts(word) != target.Accepts(word) {
+			t.Errorf("VPA на слове %q расходится с языком", word)
+		}
+	}
+}
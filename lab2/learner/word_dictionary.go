@@ -0,0 +1,403 @@
+package learner
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"sort"
+)
+
+// WordDictionary - словарь ответов учителя: слово -> принадлежность к языку
+// Слова хранятся в префиксном дереве, поэтому общий префикс слов таблицы (префикс строки)
+// хранится один раз, а символы на рёбрах - номерами из общей таблицы символов.
+// Если задан предел maxWords, при его достижении все слова из памяти сбрасываются в файл
+// отсортированным сегментом; в памяти от сегмента остаются только разреженный индекс
+// и фильтр Блума, по которому отсутствующие слова отсекаются без чтения файла
+type WordDictionary struct {
+	epsilon  string
+	ids      map[rune]uint32 // Символ -> номер
+	nodes    []trieNode      // Узел 0 - корень (пустое слово)
+	inMemory int             // Слов в дереве
+	size     int             // Слов всего, включая сброшенные в файл
+
+	maxWords    int    // Предел слов в памяти; 0 - без сброса в файл
+	spillPath   string // Файл для сброса; пусто - временный файл
+	spill       *os.File
+	temporary   bool // Файл сброса создан словарём как временный и удаляется в Close
+	spillEnd    int64
+	segments    []*spillSegment
+	overwritten map[string]bool // Слова, ответ на которые менялся: в файле может быть старая копия
+	err         error           // Первая ошибка работы с файлом сброса
+}
+
+// trieNode - узел дерева: потомки образуют список через sibling
+type trieNode struct {
+	child   uint32 // Первый потомок; 0 - потомков нет (корень не бывает потомком)
+	sibling uint32 // Следующий потомок того же родителя
+	symbol  uint32 // Номер символа на ребре от родителя
+	answer  Cell   // CellUnknown - такого слова в словаре нет
+}
+
+// spillBlock - через сколько записей сегмента ставится запись разреженного индекса
+const spillBlock = 32
+
+// spillSegment - отсортированные слова, сброшенные в файл одним куском
+type spillSegment struct {
+	offset, end int64
+	count       int
+	index       []spillIndexEntry
+	bloom       []uint64
+}
+
+// spillIndexEntry - первое слово блока сегмента и смещение блока в файле
+type spillIndexEntry struct {
+	word   string
+	offset int64
+}
+
+// NewWordDictionary - пустой словарь; epsilon - запись пустого слова в ключах
+func NewWordDictionary(epsilon string) *WordDictionary {
+	return &WordDictionary{
+		epsilon:     epsilon,
+		ids:         make(map[rune]uint32),
+		nodes:       make([]trieNode, 1),
+		overwritten: make(map[string]bool),
+	}
+}
+
+// SetSpill - сброс слов в файл path (пусто - временный файл), когда в памяти их становится maxWords
+func (dict *WordDictionary) SetSpill(maxWords int, path string) {
+	dict.maxWords = maxWords
+	dict.spillPath = path
+}
+
+// node - узел слова; create - создать недостающие узлы. false, если узла нет
+func (dict *WordDictionary) node(word string, create bool) (uint32, bool) {
+	current := uint32(0)
+	for _, letter := range trimEpsilon(word, dict.epsilon) {
+		id, known := dict.ids[letter]
+		if !known {
+			if !create {
+				return 0, false
+			}
+			id = uint32(len(dict.ids))
+			dict.ids[letter] = id
+		}
+
+		next := dict.nodes[current].child
+		for next != 0 && dict.nodes[next].symbol != id {
+			next = dict.nodes[next].sibling
+		}
+		if next == 0 {
+			if !create {
+				return 0, false
+			}
+			next = uint32(len(dict.nodes))
+			dict.nodes = append(dict.nodes, trieNode{symbol: id, sibling: dict.nodes[current].child})
+			dict.nodes[current].child = next
+		}
+		current = next
+	}
+	return current, true
+}
+
+// Get - ответ на слово и есть ли слово в словаре
+func (dict *WordDictionary) Get(word string) (bool, bool) {
+	if node, exists := dict.node(word, false); exists && dict.nodes[node].answer != CellUnknown {
+		return dict.nodes[node].answer == CellAccept, true
+	}
+	key := trimEpsilon(word, dict.epsilon)
+	for i := len(dict.segments) - 1; i >= 0; i-- {
+		belongs, exists, err := dict.segments[i].find(dict.spill, key)
+		if err != nil {
+			// Непрочитанное слово считается отсутствующим: учитель спросит его заново
+			dict.fail(err)
+		}
+		if exists {
+			return belongs, true
+		}
+	}
+	return false, false
+}
+
+// Belongs - ответ на слово; false, если слова нет в словаре
+func (dict *WordDictionary) Belongs(word string) bool {
+	belongs, _ := dict.Get(word)
+	return belongs
+}
+
+// Has - есть ли слово в словаре
+func (dict *WordDictionary) Has(word string) bool {
+	_, exists := dict.Get(word)
+	return exists
+}
+
+// Set - запись ответа на слово
+func (dict *WordDictionary) Set(word string, belongs bool) {
+	old, exists := dict.Get(word)
+	if exists && old == belongs {
+		return
+	}
+	node, _ := dict.node(word, true)
+	if dict.nodes[node].answer == CellUnknown {
+		dict.inMemory++
+	}
+	if exists {
+		// Старая копия могла остаться в файле
+		dict.overwritten[trimEpsilon(word, dict.epsilon)] = true
+	} else {
+		dict.size++
+	}
+	dict.nodes[node].answer = CellOf(belongs)
+
+	if dict.maxWords > 0 && dict.inMemory >= dict.maxWords {
+		if err := dict.flush(); err != nil {
+			// Словарь остаётся в памяти целиком
+			dict.fail(err)
+			dict.maxWords = 0
+		}
+	}
+}
+
+// Len - число слов в словаре
+func (dict *WordDictionary) Len() int {
+	return dict.size
+}
+
+// rangeMemory - обход слов дерева в памяти
+func (dict *WordDictionary) rangeMemory(fn func(word string, belongs bool)) {
+	symbols := make([]rune, len(dict.ids))
+	for letter, id := range dict.ids {
+		symbols[id] = letter
+	}
+	var path []rune
+	var walk func(node uint32)
+	walk = func(node uint32) {
+		if answer := dict.nodes[node].answer; answer != CellUnknown {
+			fn(string(path), answer == CellAccept)
+		}
+		for child := dict.nodes[node].child; child != 0; child = dict.nodes[child].sibling {
+			path = append(path, symbols[dict.nodes[child].symbol])
+			walk(child)
+			path = path[:len(path)-1]
+		}
+	}
+	walk(0)
+}
+
+// Range - обход всех слов словаря (пустое слово передаётся как ε словаря)
+func (dict *WordDictionary) Range(fn func(word string, belongs bool)) {
+	emitted := make(map[string]bool)
+	emit := func(word string, belongs bool) {
+		if dict.overwritten[word] {
+			// Новый ответ выдаётся первым, старые копии пропускаются
+			if emitted[word] {
+				return
+			}
+			emitted[word] = true
+		}
+		fn(orEpsilon(word, dict.epsilon), belongs)
+	}
+	dict.rangeMemory(emit)
+	for i := len(dict.segments) - 1; i >= 0; i-- {
+		if err := dict.segments[i].scan(dict.spill, emit); err != nil {
+			dict.fail(err)
+		}
+	}
+}
+
+// Bounded - задан ли предел слов в памяти: такой словарь целиком в память не выгружается
+func (dict *WordDictionary) Bounded() bool {
+	return dict.maxWords > 0
+}
+
+// Map - все слова словаря в виде map (для сохранения вместе с автоматом)
+// Для словаря с пределом слов в памяти возвращает nil
+func (dict *WordDictionary) Map() map[string]bool {
+	if dict.Bounded() {
+		return nil
+	}
+	words := make(map[string]bool, dict.size)
+	dict.Range(func(word string, belongs bool) {
+		words[word] = belongs
+	})
+	return words
+}
+
+// fail - запоминание ошибки работы с файлом сброса; сообщается первая из них
+func (dict *WordDictionary) fail(err error) {
+	if dict.err == nil {
+		dict.err = err
+	}
+}
+
+// Err - первая ошибка чтения или записи файла сброса; nil, если ошибок не было
+func (dict *WordDictionary) Err() error {
+	return dict.err
+}
+
+// Close - закрытие файла сброса; временный файл удаляется, а заданный в SetSpill остаётся.
+// Возвращает первую ошибку работы с файлом сброса за всё время работы словаря
+func (dict *WordDictionary) Close() error {
+	if dict.spill == nil {
+		return dict.err
+	}
+	path := dict.spill.Name()
+	if err := dict.spill.Close(); err != nil {
+		dict.fail(fmt.Errorf("ошибка при закрытии файла словаря: %v", err))
+	}
+	if dict.temporary {
+		if err := os.Remove(path); err != nil {
+			dict.fail(fmt.Errorf("ошибка при удалении файла словаря: %v", err))
+		}
+	}
+	dict.spill = nil
+	dict.segments = nil
+	return dict.err
+}
+
+// flush - сброс слов из памяти в файл новым сегментом
+func (dict *WordDictionary) flush() error {
+	if dict.spill == nil {
+		var err error
+		if dict.spillPath != "" {
+			dict.spill, err = os.Create(dict.spillPath)
+		} else {
+			dict.spill, err = os.CreateTemp("", "lab2-words-*.spill")
+			dict.temporary = err == nil
+		}
+		if err != nil {
+			return fmt.Errorf("ошибка при создании файла словаря: %v", err)
+		}
+	}
+
+	type entry struct {
+		word    string
+		belongs bool
+	}
+	var entries []entry
+	dict.rangeMemory(func(word string, belongs bool) {
+		entries = append(entries, entry{word, belongs})
+	})
+	sort.Slice(entries, func(i, j int) bool { return entries[i].word < entries[j].word })
+
+	segment := &spillSegment{offset: dict.spillEnd, count: len(entries), bloom: make([]uint64, (len(entries)*10+63)/64+1)}
+	writer := bufio.NewWriter(io.NewOffsetWriter(dict.spill, dict.spillEnd))
+	offset := dict.spillEnd
+	buffer := make([]byte, binary.MaxVarintLen64)
+	for i, e := range entries {
+		if i%spillBlock == 0 {
+			segment.index = append(segment.index, spillIndexEntry{e.word, offset})
+		}
+		segment.addBloom(e.word)
+		n := binary.PutUvarint(buffer, uint64(len(e.word)))
+		writer.Write(buffer[:n])
+		writer.WriteString(e.word)
+		answer := byte(0)
+		if e.belongs {
+			answer = 1
+		}
+		writer.WriteByte(answer)
+		offset += int64(n + len(e.word) + 1)
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("ошибка при записи файла словаря: %v", err)
+	}
+	segment.end = offset
+	dict.spillEnd = offset
+	dict.segments = append(dict.segments, segment)
+
+	// Номера символов сохраняются: новые слова строятся из тех же символов
+	dict.nodes = dict.nodes[:1]
+	dict.nodes[0] = trieNode{}
+	dict.inMemory = 0
+	return nil
+}
+
+// bloomHashes - две независимые половины хеша слова для фильтра Блума
+func bloomHashes(word string) (uint64, uint64) {
+	hash := fnv.New64a()
+	hash.Write([]byte(word))
+	sum := hash.Sum64()
+	return sum, sum>>32 | 1
+}
+
+// addBloom - добавление слова в фильтр Блума сегмента (4 бита из 10 на слово)
+func (segment *spillSegment) addBloom(word string) {
+	h1, h2 := bloomHashes(word)
+	bits := uint64(len(segment.bloom) * 64)
+	for i := uint64(0); i < 4; i++ {
+		bit := (h1 + i*h2) % bits
+		segment.bloom[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// mayContain - false, если слова в сегменте точно нет
+func (segment *spillSegment) mayContain(word string) bool {
+	h1, h2 := bloomHashes(word)
+	bits := uint64(len(segment.bloom) * 64)
+	for i := uint64(0); i < 4; i++ {
+		bit := (h1 + i*h2) % bits
+		if segment.bloom[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// find - поиск слова в сегменте: по индексу находится блок, который читается из файла
+func (segment *spillSegment) find(file *os.File, word string) (bool, bool, error) {
+	if !segment.mayContain(word) {
+		return false, false, nil
+	}
+	block := sort.Search(len(segment.index), func(i int) bool { return segment.index[i].word > word }) - 1
+	if block < 0 {
+		return false, false, nil
+	}
+	end := segment.end
+	if block+1 < len(segment.index) {
+		end = segment.index[block+1].offset
+	}
+
+	belongs, found := false, false
+	reader := bufio.NewReader(io.NewSectionReader(file, segment.index[block].offset, end-segment.index[block].offset))
+	err := readSpillRecords(reader, func(record string, answer bool) bool {
+		if record == word {
+			belongs, found = answer, true
+		}
+		return !found && record < word
+	})
+	return belongs, found, err
+}
+
+// scan - обход всех слов сегмента по порядку
+func (segment *spillSegment) scan(file *os.File, fn func(word string, belongs bool)) error {
+	reader := bufio.NewReader(io.NewSectionReader(file, segment.offset, segment.end-segment.offset))
+	return readSpillRecords(reader, func(word string, belongs bool) bool {
+		fn(word, belongs)
+		return true
+	})
+}
+
+// readSpillRecords - чтение записей "длина, слово, ответ", пока fn возвращает true
+func readSpillRecords(reader *bufio.Reader, fn func(word string, belongs bool) bool) error {
+	for {
+		length, err := binary.ReadUvarint(reader)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("ошибка при чтении файла словаря: %v", err)
+		}
+		record := make([]byte, length+1)
+		if _, err := io.ReadFull(reader, record); err != nil {
+			return fmt.Errorf("ошибка при чтении файла словаря: %v", err)
+		}
+		if !fn(string(record[:length]), record[length] == 1) {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package learner
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// checkDictionary - сравнение Get, Range и Len словаря с эталонным map
+func checkDictionary(t *testing.T, dict *WordDictionary, reference map[string]bool, missing []string) {
+	t.Helper()
+	for word, want := range reference {
+		if belongs, exists := dict.Get(word); !exists || belongs != want {
+			t.Errorf("Get(%q) = %v, %v, ожидалось %v, true", word, belongs, exists, want)
+		}
+	}
+	for _, word := range missing {
+		if _, exists := dict.Get(word); exists {
+			t.Errorf("Get(%q): слова нет в словаре, но оно найдено", word)
+		}
+	}
+
+	seen := make(map[string]bool)
+	dict.Range(func(word string, belongs bool) {
+		if seen[word] {
+			t.Errorf("Range выдал слово %q дважды", word)
+		}
+		seen[word] = true
+		if want, exists := reference[word]; !exists || belongs != want {
+			t.Errorf("Range: %q = %v, в эталоне %v, %v", word, belongs, want, exists)
+		}
+	})
+	if len(seen) != len(reference) {
+		t.Errorf("Range выдал %d слов, ожидалось %d", len(seen), len(reference))
+	}
+	if dict.Len() != len(reference) {
+		t.Errorf("Len() = %d, ожидалось %d", dict.Len(), len(reference))
+	}
+}
+
+func TestWordDictionarySpill(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.spill")
+	dict := NewWordDictionary("ε")
+	dict.SetSpill(7, path)
+
+	words := allWords("abc", 4)
+	random := rand.New(rand.NewSource(1))
+	reference := make(map[string]bool)
+	var missing []string
+	for i, word := range words {
+		if i%5 == 4 {
+			missing = append(missing, orEpsilon(word, "ε"))
+			continue
+		}
+		key := orEpsilon(word, "ε")
+		reference[key] = random.Intn(2) == 0
+		dict.Set(key, reference[key])
+	}
+	if len(dict.segments) < 3 {
+		t.Fatalf("сброшено сегментов: %d, ожидалось несколько", len(dict.segments))
+	}
+	checkDictionary(t, dict, reference, missing)
+
+	// Ответы на слова, уже сброшенные в файл, меняются; часть новых ответов
+	// тоже успевает попасть в файл следующими сегментами
+	changed := 0
+	for i, word := range words {
+		key := orEpsilon(word, "ε")
+		if _, exists := reference[key]; !exists || i%3 != 0 {
+			continue
+		}
+		reference[key] = !reference[key]
+		dict.Set(key, reference[key])
+		changed++
+	}
+	if changed == 0 || len(dict.overwritten) != changed {
+		t.Fatalf("перезаписано слов: %d, отмечено: %d", changed, len(dict.overwritten))
+	}
+	checkDictionary(t, dict, reference, missing)
+
+	// Повторная запись того же ответа словарь не меняет
+	for word, belongs := range reference {
+		dict.Set(word, belongs)
+	}
+	checkDictionary(t, dict, reference, missing)
+
+	// Файл, заданный пользователем, словарь не удаляет
+	if err := dict.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("заданный файл сброса удалён: %v", err)
+	}
+}
+
+func TestWordDictionaryTemporarySpill(t *testing.T) {
+	dict := NewWordDictionary("ε")
+	dict.SetSpill(3, "")
+	for _, word := range allWords("ab", 3) {
+		dict.Set(orEpsilon(word, "ε"), len(word)%2 == 0)
+	}
+	if dict.spill == nil {
+		t.Fatal("слова не сброшены во временный файл")
+	}
+	path := dict.spill.Name()
+	if err := dict.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("временный файл сброса не удалён: %v", err)
+	}
+}
+
+func TestWordDictionarySpillError(t *testing.T) {
+	// Каталога нет: файл сброса не создаётся, и слова остаются в памяти
+	dict := NewWordDictionary("ε")
+	dict.SetSpill(3, filepath.Join(t.TempDir(), "missing", "words.spill"))
+	reference := make(map[string]bool)
+	for _, word := range allWords("ab", 3) {
+		key := orEpsilon(word, "ε")
+		reference[key] = len(word)%2 == 0
+		dict.Set(key, reference[key])
+	}
+	checkDictionary(t, dict, reference, nil)
+	if dict.Err() == nil {
+		t.Fatal("ошибка создания файла сброса не сохранена")
+	}
+	if err := dict.Close(); err == nil {
+		t.Fatal("Close не вернул ошибку создания файла сброса")
+	}
+}
+
+func TestWordDictionaryBoundedMap(t *testing.T) {
+	dict := NewWordDictionary("ε")
+	dict.Set("ab", true)
+	if words := dict.Map(); len(words) != 1 || !words["ab"] {
+		t.Fatalf("Map() = %v, ожидалось map[ab:true]", words)
+	}
+	dict.SetSpill(100, filepath.Join(t.TempDir(), "words.spill"))
+	if words := dict.Map(); words != nil {
+		t.Fatalf("Map() словаря с пределом = %v, ожидалось nil", words)
+	}
+	dict.Close()
+}
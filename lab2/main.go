@@ -1,320 +1,173 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"lab2/learner"
+	"os"
 	"strings"
 	"time"
 )
 
-var learnerMode, server, port string
-var counterTrueWords int
+// configPath - файл конфигурации обучения
+// const configPath = "/home/alexandr/BMSTU_git/IU9-ToFL/lab2/config.json"
+const configPath = "E:/BMSTU_git/IU9-ToFL/lab2/config.json"
 
 func main() {
-	counterTrueWords = 0
-	heuristicAdded := false
-	config, err := LoadConfig()
+	// Подкоманды, не требующие обучения
+	if len(os.Args) > 1 {
+		var command func([]string) error
+		switch os.Args[1] {
+		case "check":
+			command = runCheck
+		case "export":
+			command = runExport
+		case "diff":
+			command = runDiff
+		case "passive":
+			command = runPassive
+		}
+		if command != nil {
+			if err := command(os.Args[2:]); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	config, err := learner.LoadConfig(configPath)
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
-	alphabet := config.Alphabet
-	epsilon := config.Epsilon
-	learnerMode = config.LearnerMode
-	server = config.ServerAddr
-	port = config.ServerPort
-	matMode := config.MatMode
-	eolAlphabet := ""
-
-	maxLexemeSize, _ := SetModeForMAT(matMode)
-	log.Printf("Максимальный размер лексеммы: %d", maxLexemeSize)
 
 	// Время старта
 	start := time.Now()
 
-	IsDone := false
-
-	// Инициализируем таблицу с картами префиксов и суффиксов
-	prefixes := map[string]Prefix{
-		epsilon: {Value: epsilon, IsMain: true},
+	session := learner.NewLearner(config)
+	session.Hooks = &learner.Hooks{
+		OnInconsistency: func(prefix1, prefix2, letter, suffix string) {
+			fmt.Println("inconsistency!")
+		},
+		OnOutputCounterexample: func(word string) {
+			fmt.Printf("Контрпример: %s\n", word)
+		},
 	}
-	suffixes := map[string]string{epsilon: epsilon}
-
-	et := NewEquivalenceTable(prefixes, suffixes)
-	useEol := true
 
-	// Пока таблица не угадана
-	for !IsDone {
-		wordsToAsk := make(map[string]PrefixAndSuffixForWord)
-		// Заполняем пустые значения таблицы
-		for _, prefix := range et.Prefixes {
-			for _, suffix := range et.Suffixes {
-				// Если ячейка пуста
-				if et.GetValue(prefix.Value, suffix) == '0' {
-					currentPrefix := prefix.Value
-					currentSuffix := suffix
-					var word string
-					// Избавляемся от ε
-					if currentPrefix == "ε" && currentSuffix == "ε" {
-						word = "ε"
-					} else if currentPrefix == "ε" {
-						currentPrefix = ""
-						word = currentPrefix + currentSuffix
-					} else if currentSuffix == "ε" {
-						currentSuffix = ""
-						word = currentPrefix + currentSuffix
-					} else {
-						word = currentPrefix + currentSuffix
-					}
-					// Проверяем наличие слова в словаре
-					if et.CheckWord(word) {
-						// Если слово принадлежит языку
-						if et.Words[word] {
-							et.Update(prefix.Value, suffix, '+')
-						} else {
-							et.Update(prefix.Value, suffix, '-')
-						}
-					} else {
-						// Иначе сохраняем для вопроса
-						// Проверяем, существует ли уже такое слово в карте
-						if _, exists := wordsToAsk[word]; !exists {
-							// Если слова нет, создаем новую запись с пустым списком пар
-							wordsToAsk[word] = PrefixAndSuffixForWord{
-								Pairs: make([]Pair, 0),
-							}
-						}
-						prefixSuffix := wordsToAsk[word]
-						// Добавляем текущие префикс и суффикс в список пар для данного слова
-						prefixSuffix.Pairs = append(wordsToAsk[word].Pairs, Pair{
-							First:  prefix.Value,
-							Second: suffix,
-						})
-						wordsToAsk[word] = prefixSuffix
-					}
-				}
-			}
+	var stats learner.Stats
+	if config.Algorithm == "moore" || config.Algorithm == "mealy" {
+		var machine *learner.MooreMachine
+		machine, stats, err = session.LearnOutputs(context.Background())
+		if err != nil {
+			fmt.Println(err)
+			return
 		}
-		// Создаем дополнение таблицы для префиксов
-		for _, oldPrefix := range et.Prefixes {
-			// Для каждого символа алфавита
-			for _, letter := range alphabet {
-				if strings.Contains(eolAlphabet, string(letter)) && !useEol {
-					continue
-				}
-				// Создаем новые префиксы на основе главных префиксов
-				if oldPrefix.IsMain {
-					currentPrefix := oldPrefix.Value
-					if currentPrefix == "ε" {
-						currentPrefix = ""
-					}
-					prefix := Prefix{
-						Value:  currentPrefix + string(letter),
-						IsMain: false,
-					}
-					// Если префикс удалось добавить
-					if et.AddPrefix(prefix) {
-						// По необходимости задаём вопросы MAT, заполняем таблицу
-						for _, suffix := range et.Suffixes {
-							// Убираем ε-суффикс
-							currentSuffix := suffix
-							if currentSuffix == "ε" {
-								currentSuffix = ""
-							}
-							word := prefix.Value + currentSuffix
-
-							// Проверяем наличие слова в словаре
-							if et.CheckWord(word) {
-								// Если слово принадлежит языку
-								if et.Words[word] {
-									et.Update(prefix.Value, suffix, '+')
-								} else {
-									et.Update(prefix.Value, suffix, '-')
-								}
-							} else {
-								// Проверяем, существует ли уже такое слово в карте
-								if _, exists := wordsToAsk[word]; !exists {
-									// Если слова нет, создаем новую запись с пустым списком пар
-									wordsToAsk[word] = PrefixAndSuffixForWord{
-										Pairs: make([]Pair, 0),
-									}
-								}
-								prefixSuffix := wordsToAsk[word]
-								// Добавляем текущие префикс и суффикс в список пар для данного слова
-								prefixSuffix.Pairs = append(wordsToAsk[word].Pairs, Pair{
-									First:  prefix.Value,
-									Second: suffix,
-								})
-								wordsToAsk[word] = prefixSuffix
-							}
-						}
-					}
-				}
+		saveOutputMachine(machine, config)
+	} else {
+		var hypothesis *learner.DFA
+		hypothesis, stats, err = session.Learn(context.Background())
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		printSeedStats(stats, config)
+		if config.PruneSuffixes {
+			fmt.Printf("Суффиксов до сокращения: %d, после: %d\n", stats.SuffixesBeforePruning, stats.SuffixesBeforePruning-stats.PrunedSuffixes)
+			if config.RecheckPruned && stats.PrunedSuffixes > 0 {
+				fmt.Println("Сокращённая таблица подтверждена учителем")
 			}
 		}
-		et.AskForWordBatch(wordsToAsk)
-
-		//wordsToAsk = make([]string, 0)
-		wordsToAsk = make(map[string]PrefixAndSuffixForWord)
-		// Проверяем таблицу на полноту и приводим к полному виду
-		et.CompleteTable()
-
-		// Проверка, являются ли все префиксы главными
-		if !et.AreAllPrefixesMain() {
+		saveCompanions(session.Companions, hypothesis, config.HypothesisFile)
+		saveHypothesis(hypothesis, config.HypothesisFile)
+	}
+	fmt.Printf("Вопросов о принадлежности: %d, гипотез: %d\n", stats.MembershipQueries, stats.EquivalenceQueries)
+	if stats.SavedExtensionQueries > 0 {
+		fmt.Printf("Сэкономлено вопросов о продолжениях: %d\n", stats.SavedExtensionQueries)
+	}
+	// Засекаем время
+	finish := time.Since(start)
+	fmt.Printf("Время выполнения программы: %s\n", finish)
+}
 
-			inconsistency := true
-			for inconsistency {
-				if et.InconsistencyTable(alphabet) {
-					fmt.Println("inconsistency!")
-					// Заполняем пустые значения таблицы
-					for _, prefix := range et.Prefixes {
-						for _, suffix := range et.Suffixes {
-							// Если ячейка пуста
-							if et.GetValue(prefix.Value, suffix) == '0' {
-								currentPrefix := prefix.Value
-								currentSuffix := suffix
-								var word string
-								// Избавляемся от ε
-								if currentPrefix == "ε" && currentSuffix == "ε" {
-									word = "ε"
-								} else if currentPrefix == "ε" {
-									currentPrefix = ""
-									word = currentPrefix + currentSuffix
-								} else if currentSuffix == "ε" {
-									currentSuffix = ""
-									word = currentPrefix + currentSuffix
-								} else {
-									word = currentPrefix + currentSuffix
-								}
-								// Проверяем наличие слова в словаре
-								if et.CheckWord(word) {
-									// Если слово принадлежит языку
-									if et.Words[word] {
-										et.Update(prefix.Value, suffix, '+')
-									} else {
-										et.Update(prefix.Value, suffix, '-')
-									}
-								} else { // Иначе спрашиваем
-									// Проверяем, существует ли уже такое слово в карте
-									if _, exists := wordsToAsk[word]; !exists {
-										// Если слова нет, создаем новую запись с пустым списком пар
-										wordsToAsk[word] = PrefixAndSuffixForWord{
-											Pairs: make([]Pair, 0),
-										}
-									}
-									prefixSuffix := wordsToAsk[word]
-									// Добавляем текущие префикс и суффикс в список пар для данного слова
-									prefixSuffix.Pairs = append(wordsToAsk[word].Pairs, Pair{
-										First:  prefix.Value,
-										Second: suffix,
-									})
-									wordsToAsk[word] = prefixSuffix
-								}
-							}
-						}
-					}
+// printSeedStats - что загружено из выборки seed_file и прежнего автомата previous_hypothesis
+func printSeedStats(stats learner.Stats, config *learner.Config) {
+	if config.SeedFile != "" {
+		fmt.Printf("Из выборки загружено слов: %d, префиксов: %d, суффиксов: %d\n", stats.SeedWords, stats.SeedPrefixes, stats.SeedSuffixes)
+	}
+	if config.PreviousHypothesis != "" {
+		fmt.Printf("Прежний автомат: префиксов %d, суффиксов %d, слов перепроверено %d, изменилось %d\n",
+			stats.PreviousPrefixes, stats.PreviousSuffixes, stats.RevalidatedWords, len(stats.ChangedWords))
+		for _, word := range stats.ChangedWords {
+			fmt.Printf("  ответ изменился: %s\n", word)
+		}
+	}
+}
 
-					et.AskForWordBatch(wordsToAsk)
-					wordsToAsk = make(map[string]PrefixAndSuffixForWord)
-				} else {
-					inconsistency = false
-				}
-			}
+// saveHypothesis - сохранение угаданного автомата с сообщением о результате
+func saveHypothesis(hypothesis *learner.DFA, path string) {
+	if err := learner.SaveDFA(hypothesis, path); err != nil {
+		fmt.Println(err)
+	} else {
+		fmt.Printf("Автомат сохранён в %s\n", path)
+	}
+}
 
-			// отправляем таблицу MAT
-			response, responseType := et.AskForTable()
-			// Если угадали, то конец, меняем флаг, иначе - добавляем новые суффиксы
-			if response == "true" {
-				IsDone = true
-			} else {
-				if responseType == "true" {
-					// fmt.Printf("Контрпример лернера: %s\n", response)
-					et.Words[response] = true
-				} else {
-					// fmt.Printf("Контрпример мата: %s\n", response)
+// saveOutputMachine - сохранение машины Мура или, для mealy, машины Мили
+func saveOutputMachine(machine *learner.MooreMachine, config *learner.Config) {
+	var err error
+	if config.Algorithm == "mealy" {
+		err = learner.SaveJSON(machine.ToMealy(), config.HypothesisFile)
+	} else {
+		err = learner.SaveJSON(machine, config.HypothesisFile)
+	}
+	if err != nil {
+		fmt.Println(err)
+	} else {
+		fmt.Printf("Автомат сохранён в %s\n", config.HypothesisFile)
+	}
+}
 
-					et.Words[response] = false
-				}
-				for i := 0; i < len(response); i++ {
-					et.AddSuffix(response[i:])
-				}
-				_, removedNumber := RemoveChars(eolAlphabet, response)
-				if removedNumber > 0 {
-					// fmt.Println("Используем eol")
-					useEol = true
-				} else {
-					// fmt.Println("Не используем eol")
-					useEol = false
-				}
-				//if heuristicAdded {
-				//	fmt.Printf("Добавил контпример в префиксы: %s\n", response)
-				//	prefix := Prefix{
-				//		Value:  response,
-				//		IsMain: true,
-				//	}
-				//	et.AddPrefix(prefix)
-				//}
-			}
+// saveCompanions - вывод и сохранение автоматов, изученных вместе с гипотезой,
+// рядом с ней в файлы *.vpa.json, *.sym.json, *.counter.json и *.nfa.json
+func saveCompanions(companions learner.Companions, hypothesis *learner.DFA, hypothesisFile string) {
+	save := func(automaton interface{}, kind, title string) {
+		path := companionFile(hypothesisFile, kind)
+		if err := learner.SaveJSON(automaton, path); err != nil {
+			fmt.Println(err)
+		} else {
+			fmt.Printf("%s сохранён в %s\n", title, path)
 		}
-		// fmt.Printf("Количество угаданных слов: %d\n", counterTrueWords)
-
-		counterEolAlphabets := 0
-		if counterTrueWords > 5000 && !heuristicAdded {
-			heuristicAdded = true
-			OriginalWordsToAsk := make(map[string]PrefixAndSuffixForWord)
-			for word := range et.Words {
-				if et.Words[word] {
-					OriginalWordsToAsk[word] = PrefixAndSuffixForWord{}
-				}
-			}
-			eolFindFlag := false
-			for length := len(alphabet) - 4; length > 0; length-- {
-				if eolFindFlag {
-					break
-				}
-				combinations := generateCombinations(alphabet, length)
-				for _, subAlphabet := range combinations {
-					NewWordsToAsk := make(map[string]PrefixAndSuffixForWord)
-					// fmt.Printf("Проверка для подалфавита: %s\n", subAlphabet)
-					emptyWord := false
-					for word := range OriginalWordsToAsk {
-						NewWord, _ := RemoveChars(subAlphabet, word)
-						if NewWord == "" {
-							emptyWord = true
-							break
-						}
-						NewWordsToAsk[NewWord] = PrefixAndSuffixForWord{}
-					}
-					if !emptyWord {
-						responseList := et.AskForWordBatch(NewWordsToAsk)
-						// countingOfFalse := 0
-						responseWithFalse := false
-						for _, response := range responseList {
-							if !response {
-								responseWithFalse = true
-							}
-						}
-						// fmt.Printf("Число ошибок: %d\n", countingOfFalse)
-						if !responseWithFalse {
-							if eolAlphabet != "" {
-								eolAlphabet = Intersection(eolAlphabet, subAlphabet)
-							} else {
-								eolAlphabet = subAlphabet
-							}
+	}
 
-							fmt.Printf("Алфавит для eol: %s\n", eolAlphabet)
-							counterEolAlphabets++
-							eolFindFlag = true
-							//break
-						}
-					}
-				}
-			}
-			fmt.Printf("Количество найденных алфавитов: %d\n", counterEolAlphabets)
+	if vpa := companions.VPA; vpa != nil {
+		fmt.Printf("Состояний VPA: %d, состояний ДКА до глубины %d: %d\n", len(vpa.States), companions.MaxDepth, len(hypothesis.States))
+		save(vpa, "vpa", "Автомат с магазинной памятью")
+	}
+	if symbolic := companions.Symbolic; symbolic != nil {
+		fmt.Print(symbolic)
+		save(symbolic, "sym", "Символьный автомат")
+	}
+	if nfa := companions.NFA; nfa != nil {
+		fmt.Printf("Состояний в NFA: %d, в ДКА после детерминизации: %d\n", len(nfa.States), len(hypothesis.States))
+		path := companionFile(hypothesisFile, "nfa")
+		if err := learner.SaveNFA(nfa, path); err != nil {
+			fmt.Println(err)
+		} else {
+			fmt.Printf("Резидуальный NFA сохранён в %s\n", path)
 		}
-
 	}
-	// et.PrintTable()
-	// Засекаем время
-	finish := time.Since(start)
-	fmt.Printf("Время выполнения программы: %s\n", finish)
+	if companions.CounterError != nil {
+		fmt.Printf("Предупреждение: ДКА не сжат в автомат со счётчиком: %v\n", companions.CounterError)
+	}
+	if counter := companions.Counter; counter != nil {
+		fmt.Print(counter)
+		fmt.Printf("Состояний автомата со счётчиком: %d, состояний ДКА: %d\n", len(counter.States), len(hypothesis.States))
+		save(counter, "counter", "Автомат со счётчиком")
+	}
+}
+
+// companionFile - имя файла рядом с гипотезой: hypothesis.json -> hypothesis.<kind>.json
+func companionFile(hypothesisFile, kind string) string {
+	return strings.TrimSuffix(hypothesisFile, ".json") + "." + kind + ".json"
 }
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"lab2/learner"
+	"strings"
+)
+
+// runPassive - команда passive: обучение по файлу размеченных слов без учителя
+func runPassive(args []string) error {
+	flags := flag.NewFlagSet("passive", flag.ContinueOnError)
+	method := flags.String("method", "edsm", "метод склейки состояний: rpni или edsm")
+	alphabet := flags.String("alphabet", "", "алфавит (по умолчанию - символы выборки)")
+	output := flags.String("o", "hypothesis.json", "файл для сохранения автомата")
+	epsilon := flags.String("epsilon", "ε", "запись пустого слова в выборке и автомате")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("использование: passive [-method rpni|edsm] [-alphabet abc] [-o hypothesis.json] [-epsilon ε] samples.txt")
+	}
+
+	samples, err := learner.LoadSamples(flags.Arg(0), *epsilon)
+	if err != nil {
+		return err
+	}
+	if *alphabet == "" {
+		*alphabet = learner.SamplesAlphabet(samples)
+	}
+
+	dfa, err := learner.LearnPassive(samples, *alphabet, strings.ToLower(*method), *epsilon)
+	if err != nil {
+		return err
+	}
+	positive := 0
+	for _, belongs := range samples {
+		if belongs {
+			positive++
+		}
+	}
+	fmt.Printf("Выборка: %d слов (+%d / -%d), состояний: %d\n", len(samples), positive, len(samples)-positive, len(dfa.States))
+	saveHypothesis(dfa, *output)
+	return nil
+}